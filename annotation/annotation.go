@@ -1,15 +1,26 @@
 package annotation
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
 
 type AnnotationRegister interface {
 	ResolveAnnotations(annotationDocline []string) []Annotation
 	ResolveAnnotationByName(annotationDocline []string, name string) (Annotation, bool)
 	ResolveAnnotation(annotationDocline string) (Annotation, bool)
+	FindAnnotation(resolved []Annotation, annotationDocline []string, name string) (Annotation, bool)
+	ValidateAnnotation(annotationDocline string) []error
+	ResolveDefaults(annotationDocline []string) []Annotation
+	ValidateDefaults(annotationDocline string) []error
+	WithDefaults(defaults []Annotation) AnnotationRegister
+	ValidateRules(target Target, annotations []Annotation, enclosing []Annotation) []error
 }
 
 type annotationRegistry struct {
 	descriptors []AnnotationDescriptor
+	defaults    []Annotation
 }
 
 func NewRegistry(descriptors []AnnotationDescriptor) AnnotationRegister {
@@ -18,23 +29,51 @@ func NewRegistry(descriptors []AnnotationDescriptor) AnnotationRegister {
 	}
 }
 
-type Annotation struct {
-	Name       string
-	Attributes map[string]string
-}
+// Annotation is the model.Annotation, so that resolved annotations can be attached to the model
+type Annotation = model.Annotation
 
 type validationFunc func(annot Annotation) bool
 
+// ParamType is the type of the value of an annotation-parameter
+type ParamType string
+
+const (
+	ParamTypeString     ParamType = model.AnnotationValueString
+	ParamTypeBool       ParamType = model.AnnotationValueBool
+	ParamTypeInt        ParamType = model.AnnotationValueInt
+	ParamTypeFloat      ParamType = model.AnnotationValueFloat
+	ParamTypeList       ParamType = model.AnnotationValueList
+	ParamTypeAnnotation ParamType = model.AnnotationValueAnnotation
+)
+
+// Target is a kind of declaration that an annotation can be used on
+type Target string
+
+const (
+	TargetStruct    Target = "struct"
+	TargetField     Target = "field"
+	TargetOperation Target = "operation"
+	TargetEnum      Target = "enum"
+)
+
 type AnnotationDescriptor struct {
-	Name       string
-	ParamNames []string
-	Validator  validationFunc
+	Name              string
+	Description       string
+	ParamNames        []string
+	ParamTypes        map[string]ParamType // parameters without type accept any value
+	ParamDescriptions map[string]string
+	Required          []string // parameters that must be present
+	Unique            bool     // at most once per declaration
+	ExclusiveWith     []string // annotations that cannot be combined with this one on a declaration
+	RequiresEnclosing []string // annotations that the struct the declaration is part of must have
+	Targets           []Target // declarations the annotation can be used on: any when empty
+	Validator         validationFunc
 }
 
 func (ar *annotationRegistry) ResolveAnnotations(annotationDocline []string) []Annotation {
 	annotations := []Annotation{}
-	for _, line := range annotationDocline {
-		ann, ok := ar.ResolveAnnotation(strings.TrimSpace(line))
+	for _, statement := range resolveStatements(annotationDocline) {
+		ann, ok := ar.ResolveAnnotation(strings.TrimSpace(statement.text))
 		if ok {
 			annotations = append(annotations, ann)
 		}
@@ -43,8 +82,8 @@ func (ar *annotationRegistry) ResolveAnnotations(annotationDocline []string) []A
 }
 
 func (ar *annotationRegistry) ResolveAnnotationByName(annotationDocline []string, name string) (Annotation, bool) {
-	for _, line := range annotationDocline {
-		ann, ok := ar.ResolveAnnotation(strings.TrimSpace(line))
+	for _, statement := range resolveStatements(annotationDocline) {
+		ann, ok := ar.ResolveAnnotation(strings.TrimSpace(statement.text))
 		if ok && ann.Name == name {
 			return ann, true
 		}
@@ -52,6 +91,22 @@ func (ar *annotationRegistry) ResolveAnnotationByName(annotationDocline []string
 	return Annotation{}, false
 }
 
+// FindAnnotation looks up the annotation in the annotations that the parser attached: the doc-lines are only
+// resolved when these mention the annotation, like for a model that was built by hand or with another registry
+func (ar *annotationRegistry) FindAnnotation(resolved []Annotation, annotationDocline []string, name string) (Annotation, bool) {
+	for _, ann := range resolved {
+		if ann.Name == name {
+			return ann, true
+		}
+	}
+	for _, line := range annotationDocline {
+		if strings.Contains(line, "@"+name) {
+			return ar.ResolveAnnotationByName(annotationDocline, name)
+		}
+	}
+	return Annotation{}, false
+}
+
 func (ar *annotationRegistry) ResolveAnnotation(annotationDocline string) (Annotation, bool) {
 	for _, descriptor := range ar.descriptors {
 		ann, err := parseAnnotation(annotationDocline)
@@ -63,6 +118,10 @@ func (ar *annotationRegistry) ResolveAnnotation(annotationDocline string) (Annot
 			continue
 		}
 
+		ann = ar.applyDefaults(ann)
+		// an invalid value does not drop the annotation: it is reported by ValidateAnnotation
+		ann, _ = descriptor.convertValues(ann)
+
 		ok := descriptor.Validator(ann)
 		if !ok {
 			continue
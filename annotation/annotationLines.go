@@ -0,0 +1,91 @@
+package annotation
+
+import "strings"
+
+// annotationStatement is the text of an annotation that may continue across several comment-lines, example:
+//
+//	// @RestOperation( method = "GET", path = "/person/{uid}",
+//	//     roles = ["admin", "user"] )
+type annotationStatement struct {
+	text string
+	line int // offset of its first line within the comment
+}
+
+// splitCommentLines returns the lines of the comments without comment-markers: a block-comment
+// provides each of its lines, without the '*' that decorates them
+func splitCommentLines(docLines []string) []string {
+	lines := []string{}
+	for _, docLine := range docLines {
+		trimmed := strings.TrimSpace(docLine)
+		if !strings.HasPrefix(trimmed, "/*") {
+			lines = append(lines, strings.TrimPrefix(trimmed, "//"))
+			continue
+		}
+		block := strings.TrimSuffix(strings.TrimPrefix(trimmed, "/*"), "*/")
+		for _, line := range strings.Split(block, "\n") {
+			lines = append(lines, strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		}
+	}
+	return lines
+}
+
+// joinAnnotationLines joins a line that starts with an annotation whose parentheses are not closed,
+// with the lines that follow until they are. An annotation that is never closed remains on its own line.
+func joinAnnotationLines(lines []string) []annotationStatement {
+	statements := []annotationStatement{}
+	for idx := 0; idx < len(lines); idx++ {
+		statement := annotationStatement{text: lines[idx], line: idx}
+		if strings.HasPrefix(strings.TrimSpace(lines[idx]), "@") && openParentheses(lines[idx]) > 0 {
+			joined := lines[idx]
+			for end := idx + 1; end < len(lines); end++ {
+				joined += " " + strings.TrimSpace(lines[end])
+				if openParentheses(joined) <= 0 {
+					statement.text = joined
+					idx = end
+					break
+				}
+			}
+		}
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// openParentheses returns the number of parentheses of the annotation in the text that are not closed:
+// those in string-literals and those after the annotation do not count
+func openParentheses(text string) int {
+	start := strings.Index(text, "@")
+	if start < 0 {
+		return 0
+	}
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range text[start:] {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return 0
+			}
+		}
+	}
+	return depth
+}
+
+// resolveStatements returns the annotation-statements of the comments, ready to be parsed
+func resolveStatements(docLines []string) []annotationStatement {
+	return joinAnnotationLines(splitCommentLines(docLines))
+}
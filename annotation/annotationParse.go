@@ -2,65 +2,160 @@ package annotation
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"text/scanner"
-)
-
-type status int
 
-const (
-	initial status = iota
-	annotationName
-	attributeName
-	attributeValue
-	done
+	"github.com/MarcGrol/golangAnnotations/model"
 )
 
+// annotationParser parses an annotation with its typed attributes, example:
+// @X( a = "A", b = true, c = 1, d = 1.5, e = ["x", "y"], f = @Y( a = "A" ) )
+type annotationParser struct {
+	s   scanner.Scanner
+	tok rune
+	err error
+}
+
 func parseAnnotation(line string) (Annotation, error) {
 	withoutComment := strings.TrimLeft(strings.TrimSpace(line), "/")
 
+	p := &annotationParser{}
+	p.s.Init(strings.NewReader(withoutComment))
+	p.s.Error = func(s *scanner.Scanner, msg string) {
+		p.fail("%s", msg)
+	}
+	p.next()
+
+	// skip the text before the annotation, including its scan-errors, example: an apostrophe
+	for p.tok != '@' && p.tok != scanner.EOF {
+		p.next()
+	}
+	p.err = nil
+	if p.tok == scanner.EOF {
+		return Annotation{Attributes: map[string]string{}}, fmt.Errorf("Missing annotation in:%s", line)
+	}
+
+	annotation := p.parseAnnotation()
+	if p.err != nil {
+		return annotation, fmt.Errorf("Invalid annotation:%s: %v", line, p.err)
+	}
+	return annotation, nil
+}
+
+func (p *annotationParser) next() {
+	p.tok = p.s.Scan()
+}
+
+func (p *annotationParser) fail(format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (p *annotationParser) expect(tok rune) bool {
+	if p.tok != tok {
+		p.fail("Expected %s, found %q", scanner.TokenString(tok), p.s.TokenText())
+		return false
+	}
+	p.next()
+	return true
+}
+
+func (p *annotationParser) parseAnnotation() Annotation {
 	annotation := Annotation{
 		Name:       "",
-		Attributes: make(map[string]string),
+		Attributes: map[string]string{},
+		Values:     map[string]model.AnnotationValue{},
 	}
 
-	var s scanner.Scanner
-	s.Init(strings.NewReader(withoutComment))
-
-	var tok rune
-	currentStatus := initial
-	var attrName string
+	if !p.expect('@') {
+		return annotation
+	}
+	annotation.Name = p.s.TokenText()
+	if !p.expect(scanner.Ident) || !p.expect('(') {
+		return annotation
+	}
+	for p.tok != ')' && p.err == nil {
+		attrName := strings.ToLower(p.s.TokenText())
+		if !p.expect(scanner.Ident) || !p.expect('=') {
+			return annotation
+		}
+		value := p.parseValue()
+		annotation.Values[attrName] = value
+		annotation.Attributes[attrName] = value.String()
+		if p.tok != ',' {
+			break
+		}
+		p.next()
+	}
+	// the closing parenthesis is the last token that is scanned: the remainder of the line is not inspected
+	if p.tok != ')' {
+		p.fail("Expected %s, found %q", scanner.TokenString(')'), p.s.TokenText())
+	}
+	return annotation
+}
 
-	for tok != scanner.EOF && currentStatus < done {
-		tok = s.Scan()
-		switch tok {
-		case '@':
-			currentStatus = annotationName
-		case '(':
-			currentStatus = attributeName
-		case '=':
-			currentStatus = attributeValue
-		case ',':
-			currentStatus = attributeName
-		case ')':
-			currentStatus = done
-		case scanner.Ident:
-			switch currentStatus {
-			case annotationName:
-				annotation.Name = s.TokenText()
-			case attributeName:
-				attrName = s.TokenText()
-			}
-		default:
-			switch currentStatus {
-			case attributeValue:
-				annotation.Attributes[strings.ToLower(attrName)] = strings.Trim(s.TokenText(), "\"")
-			}
+func (p *annotationParser) parseValue() model.AnnotationValue {
+	switch p.tok {
+	case scanner.String, scanner.RawString:
+		text, err := strconv.Unquote(p.s.TokenText())
+		if err != nil {
+			p.fail("Invalid string %s", p.s.TokenText())
+		}
+		p.next()
+		return model.AnnotationValue{Kind: model.AnnotationValueString, Text: text}
+	case scanner.Int, scanner.Float, '-':
+		return p.parseNumber()
+	case scanner.Ident:
+		text := p.s.TokenText()
+		p.next()
+		if text == "true" || text == "false" {
+			return model.AnnotationValue{Kind: model.AnnotationValueBool, Text: text}
 		}
+		return model.AnnotationValue{Kind: model.AnnotationValueString, Text: text}
+	case '[':
+		return p.parseList()
+	case '@':
+		nested := p.parseAnnotation()
+		p.next()
+		return model.AnnotationValue{Kind: model.AnnotationValueAnnotation, Annotation: &nested}
+	default:
+		p.fail("Unexpected value %q", p.s.TokenText())
+		return model.AnnotationValue{}
 	}
+}
 
-	if currentStatus != done {
-		return annotation, fmt.Errorf("Invalid completion-status %v for annotation:%s", currentStatus, line)
+func (p *annotationParser) parseNumber() model.AnnotationValue {
+	sign := ""
+	if p.tok == '-' {
+		sign = "-"
+		p.next()
 	}
-	return annotation, nil
+	kind := model.AnnotationValueInt
+	switch p.tok {
+	case scanner.Int:
+	case scanner.Float:
+		kind = model.AnnotationValueFloat
+	default:
+		p.fail("Expected number, found %q", p.s.TokenText())
+		return model.AnnotationValue{}
+	}
+	text := sign + p.s.TokenText()
+	p.next()
+	return model.AnnotationValue{Kind: kind, Text: text}
+}
+
+func (p *annotationParser) parseList() model.AnnotationValue {
+	list := model.AnnotationValue{Kind: model.AnnotationValueList, List: []model.AnnotationValue{}}
+	p.next()
+	for p.tok != ']' && p.err == nil {
+		list.List = append(list.List, p.parseValue())
+		if p.tok != ',' {
+			break
+		}
+		p.next()
+	}
+	p.expect(']')
+	return list
 }
@@ -0,0 +1,137 @@
+package annotation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ValidateRules reports the annotations of a single declaration that violate the rules of their descriptors:
+// annotations on the wrong kind of declaration, duplicates of unique annotations, combinations of exclusive annotations,
+// and missing annotations on the enclosing struct. Enclosing holds the annotations of the struct the declaration is part of: nil if there is none.
+func (ar *annotationRegistry) ValidateRules(target Target, annotations []Annotation, enclosing []Annotation) []error {
+	errs := []error{}
+	counts := map[string]int{}
+	for _, ann := range annotations {
+		counts[ann.Name]++
+	}
+
+	reported := map[string]bool{}
+	for _, ann := range annotations {
+		if reported[ann.Name] {
+			continue
+		}
+		reported[ann.Name] = true
+
+		descriptor, ok := ar.findDescriptor(ann.Name)
+		if !ok {
+			continue
+		}
+		if !descriptor.allowsTarget(target) {
+			errs = append(errs, fmt.Errorf("Misplaced annotation @%s: it can be used on %s only, not on %ss", ann.Name, joinTargets(descriptor.Targets), target))
+			continue
+		}
+		if descriptor.Unique && counts[ann.Name] > 1 {
+			errs = append(errs, fmt.Errorf("Duplicate annotation @%s: it can be used only once", ann.Name))
+		}
+		for _, other := range ar.exclusiveWith(descriptor) {
+			// report each conflicting pair once: at the annotation that comes first
+			if counts[other] > 0 && !reported[other] {
+				errs = append(errs, fmt.Errorf("Conflicting annotations @%s and @%s: these cannot be combined", ann.Name, other))
+			}
+		}
+		for _, required := range descriptor.RequiresEnclosing {
+			if !containsAnnotation(enclosing, required) {
+				errs = append(errs, fmt.Errorf("Annotation @%s requires @%s on the enclosing struct", ann.Name, required))
+			}
+		}
+	}
+	return errs
+}
+
+func (descriptor AnnotationDescriptor) allowsTarget(target Target) bool {
+	if len(descriptor.Targets) == 0 {
+		return true
+	}
+	for _, t := range descriptor.Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+func joinTargets(targets []Target) string {
+	names := []string{}
+	for _, target := range targets {
+		names = append(names, string(target)+"s")
+	}
+	return strings.Join(names, " or ")
+}
+
+// exclusiveWith returns the annotations that exclude the annotation, whichever of both declares it
+func (ar *annotationRegistry) exclusiveWith(descriptor AnnotationDescriptor) []string {
+	exclusive := append([]string{}, descriptor.ExclusiveWith...)
+	for _, other := range ar.descriptors {
+		for _, name := range other.ExclusiveWith {
+			if name == descriptor.Name && !contains(exclusive, other.Name) {
+				exclusive = append(exclusive, other.Name)
+			}
+		}
+	}
+	return exclusive
+}
+
+func containsAnnotation(annotations []Annotation, name string) bool {
+	for _, ann := range annotations {
+		if ann.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAnnotationRules applies the rules of the descriptors to the annotations that AttachAnnotations attached,
+// across all generators, and returns the violations as errors that point to the declaration
+func ValidateAnnotationRules(registry AnnotationRegister, parsedSources model.ParsedSources) []diagnostics.Diagnostic {
+	problems := []diagnostics.Diagnostic{}
+	report := func(position model.Position, errs []error) {
+		for _, err := range errs {
+			problems = append(problems, diagnostics.Errorf(position, "%s", err))
+		}
+	}
+
+	structNames := map[string]bool{}
+	for _, mStruct := range parsedSources.Structs {
+		structNames[mStruct.Name] = true
+		report(mStruct.Position, registry.ValidateRules(TargetStruct, mStruct.Annotations, nil))
+		for _, mField := range mStruct.Fields {
+			report(mField.Position, registry.ValidateRules(TargetField, mField.Annotations, mStruct.Annotations))
+		}
+		for _, mOperation := range mStruct.Operations {
+			report(mOperation.Position, registry.ValidateRules(TargetOperation, mOperation.Annotations, mStruct.Annotations))
+		}
+	}
+	for _, mOperation := range parsedSources.Operations {
+		if mOperation.RelatedStruct != nil && structNames[mOperation.RelatedStruct.TypeName] {
+			// validated as part of its struct
+			continue
+		}
+		report(mOperation.Position, registry.ValidateRules(TargetOperation, mOperation.Annotations, nil))
+	}
+	for _, mEnum := range parsedSources.Enums {
+		report(mEnum.Position, registry.ValidateRules(TargetEnum, mEnum.Annotations, nil))
+	}
+	return problems
+}
@@ -0,0 +1,92 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func rulesRegistry() AnnotationRegister {
+	return NewRegistry([]AnnotationDescriptor{
+		{
+			Name:      "RestService",
+			Unique:    true,
+			Targets:   []Target{TargetStruct},
+			Validator: validateOk,
+		},
+		{
+			Name:              "RestOperation",
+			Unique:            true,
+			RequiresEnclosing: []string{"RestService"},
+			Validator:         validateOk,
+		},
+		{
+			Name:          "EventOperation",
+			ExclusiveWith: []string{"RestOperation"},
+			Validator:     validateOk,
+		},
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	registry := rulesRegistry()
+	service := []Annotation{{Name: "RestService"}}
+
+	assert.Empty(t, registry.ValidateRules(TargetStruct, []Annotation{{Name: "RestService"}}, nil))
+	assert.Empty(t, registry.ValidateRules(TargetOperation, []Annotation{{Name: "RestOperation"}}, service))
+	assert.Empty(t, registry.ValidateRules(TargetOperation, []Annotation{{Name: "EventOperation"}, {Name: "EventOperation"}}, nil))
+
+	errs := registry.ValidateRules(TargetStruct, []Annotation{{Name: "RestService"}, {Name: "RestService"}}, nil)
+	assert.Equal(t, []string{"Duplicate annotation @RestService: it can be used only once"}, validationMessages(errs))
+
+	errs = registry.ValidateRules(TargetOperation, []Annotation{{Name: "RestOperation"}, {Name: "EventOperation"}}, service)
+	assert.Equal(t, []string{"Conflicting annotations @RestOperation and @EventOperation: these cannot be combined"}, validationMessages(errs))
+
+	errs = registry.ValidateRules(TargetOperation, []Annotation{{Name: "EventOperation"}, {Name: "RestOperation"}}, service)
+	assert.Equal(t, []string{"Conflicting annotations @EventOperation and @RestOperation: these cannot be combined"}, validationMessages(errs))
+
+	errs = registry.ValidateRules(TargetOperation, []Annotation{{Name: "RestOperation"}}, nil)
+	assert.Equal(t, []string{"Annotation @RestOperation requires @RestService on the enclosing struct"}, validationMessages(errs))
+
+	errs = registry.ValidateRules(TargetField, []Annotation{{Name: "RestService"}}, nil)
+	assert.Equal(t, []string{"Misplaced annotation @RestService: it can be used on structs only, not on fields"}, validationMessages(errs))
+}
+
+func TestValidateAnnotationRules(t *testing.T) {
+	doit := model.Operation{
+		Name:          "doit",
+		Position:      model.Position{Filename: "service.go", Line: 10, Column: 6},
+		RelatedStruct: &model.Field{TypeName: "Service"},
+		DocLines:      []string{`// @RestOperation()`, `// @EventOperation()`},
+	}
+	free := model.Operation{
+		Name:     "free",
+		Position: model.Position{Filename: "service.go", Line: 20, Column: 6},
+		DocLines: []string{`// @RestOperation()`},
+	}
+	parsedSources := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Name:       "Service",
+				Position:   model.Position{Filename: "service.go", Line: 4, Column: 6},
+				DocLines:   []string{`// @RestService()`, `// @RestService()`},
+				Operations: []*model.Operation{&doit},
+			},
+		},
+		Operations: []model.Operation{doit, free},
+	}
+	registry := rulesRegistry()
+	AttachAnnotations(registry, &parsedSources)
+
+	problems := ValidateAnnotationRules(registry, parsedSources)
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		"service.go:4:6: Duplicate annotation @RestService: it can be used only once",
+		"service.go:10:6: Conflicting annotations @RestOperation and @EventOperation: these cannot be combined",
+		"service.go:20:6: Annotation @RestOperation requires @RestService on the enclosing struct",
+	}, messages)
+}
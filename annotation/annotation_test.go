@@ -3,6 +3,7 @@ package annotation
 import (
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -99,6 +100,189 @@ func TestAnnotationWithTypicalCharacters(t *testing.T) {
 	assert.Equal(t, "/B", annotation.Attributes["b"])
 }
 
+func TestTypedValues(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{},
+			Validator:  validateOk,
+		},
+	})
+
+	ann, ok := registry.ResolveAnnotation(`// @X( s = "A", b = true, i = -3, f = 1.5, l = ["a", "b"], n = @Y( a = "A", c = [1, 2] ) )`)
+	assert.True(t, ok)
+	assert.Equal(t, model.AnnotationValueString, ann.Values["s"].Kind)
+	assert.Equal(t, model.AnnotationValueBool, ann.Values["b"].Kind)
+	assert.Equal(t, model.AnnotationValueInt, ann.Values["i"].Kind)
+	assert.Equal(t, model.AnnotationValueFloat, ann.Values["f"].Kind)
+	assert.Equal(t, model.AnnotationValueList, ann.Values["l"].Kind)
+	assert.Equal(t, model.AnnotationValueAnnotation, ann.Values["n"].Kind)
+
+	assert.Equal(t, "a,b", ann.Attributes["l"])
+	assert.True(t, ann.GetBool("b"))
+	i, ok := ann.GetInt("i")
+	assert.True(t, ok)
+	assert.Equal(t, -3, i)
+	f, ok := ann.GetFloat("f")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, f)
+	l, ok := ann.GetStrings("l")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, l)
+
+	nested, ok := ann.GetNested("n")
+	assert.True(t, ok)
+	assert.Equal(t, "Y", nested.Name)
+	assert.Equal(t, "A", nested.Attributes["a"])
+	assert.Equal(t, `@Y(a = "A", c = [1, 2])`, nested.String())
+
+	_, ok = ann.GetInt("s")
+	assert.False(t, ok)
+}
+
+func TestInvalidTypedValues(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{},
+			Validator:  validateOk,
+		},
+	})
+
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @X( l = ["a", "b" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @X( n = @Y( a = "A" )`}))
+	assert.Empty(t, registry.ResolveAnnotations([]string{`// @X( i = - )`}))
+}
+
+func TestParamTypesConvertValues(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{"b", "i", "f", "l", "s"},
+			ParamTypes: map[string]ParamType{
+				"b": ParamTypeBool,
+				"i": ParamTypeInt,
+				"f": ParamTypeFloat,
+				"l": ParamTypeList,
+				"s": ParamTypeString,
+			},
+			Validator: validateOk,
+		},
+	})
+
+	ann, ok := registry.ResolveAnnotation(`// @X( B = "true", i = "42", f = 2, l = "x, y", s = 7 )`)
+	assert.True(t, ok)
+	assert.Equal(t, model.AnnotationValueBool, ann.Values["b"].Kind)
+	assert.True(t, ann.GetBool("b"))
+	assert.Equal(t, model.AnnotationValueInt, ann.Values["i"].Kind)
+	i, _ := ann.GetInt("i")
+	assert.Equal(t, 42, i)
+	assert.Equal(t, model.AnnotationValueFloat, ann.Values["f"].Kind)
+	assert.Equal(t, model.AnnotationValueList, ann.Values["l"].Kind)
+	l, _ := ann.GetStrings("l")
+	assert.Equal(t, []string{"x", "y"}, l)
+	assert.Equal(t, "x,y", ann.Attributes["l"])
+	assert.Equal(t, model.AnnotationValueString, ann.Values["s"].Kind)
+	assert.Equal(t, "7", ann.Attributes["s"])
+}
+
+func TestParamTypesKeepInvalidValues(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{"b", "i", "n"},
+			ParamTypes: map[string]ParamType{
+				"b": ParamTypeBool,
+				"i": ParamTypeInt,
+				"n": ParamTypeAnnotation,
+			},
+			Validator: validateOk,
+		},
+	})
+
+	for _, line := range []string{`// @X( b = "maybe" )`, `// @X( i = 1.5 )`, `// @X( n = "Y" )`} {
+		// the annotation is kept with the value as written, the value is reported
+		assert.Len(t, registry.ResolveAnnotations([]string{line}), 1)
+		assert.Len(t, registry.ValidateAnnotation(line), 1)
+	}
+	assert.Empty(t, registry.ValidateAnnotation(`// @X( b = false, i = 1, n = @Y() )`))
+
+	ann, ok := registry.ResolveAnnotation(`// @X( b = "maybe", i = "2" )`)
+	assert.True(t, ok)
+	assert.Equal(t, "maybe", ann.Attributes["b"])
+	assert.False(t, ann.GetBool("b"))
+	i, ok := ann.GetInt("i")
+	assert.True(t, ok)
+	assert.Equal(t, 2, i)
+}
+
+func TestGettersOfHandBuiltAnnotation(t *testing.T) {
+	ann := Annotation{
+		Name:       "X",
+		Attributes: map[string]string{"b": "true", "l": "a, b"},
+	}
+	assert.True(t, ann.GetBool("b"))
+	l, ok := ann.GetStrings("l")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, l)
+	assert.False(t, ann.GetBool("missing"))
+}
+
+func TestMultiLineAnnotation(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{},
+			Validator:  validateOk,
+		},
+	})
+
+	annotations := registry.ResolveAnnotations([]string{
+		`// Doit does something (important)`,
+		`// @X( a = "A (",`,
+		`//     b = ["x",`,
+		`//          "y"] )`,
+		`// @X( c = "C" )`,
+	})
+	assert.Len(t, annotations, 2)
+	assert.Equal(t, "A (", annotations[0].Attributes["a"])
+	assert.Equal(t, "x,y", annotations[0].Attributes["b"])
+	assert.Equal(t, "C", annotations[1].Attributes["c"])
+}
+
+func TestBlockCommentAnnotation(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{},
+			Validator:  validateOk,
+		},
+	})
+
+	ann, ok := registry.ResolveAnnotationByName([]string{"/*\n * Doit does something\n * @X( a = \"A\",\n *     b = true )\n */"}, "X")
+	assert.True(t, ok)
+	assert.Equal(t, "A", ann.Attributes["a"])
+	assert.True(t, ann.GetBool("b"))
+
+	ann, ok = registry.ResolveAnnotationByName([]string{`/* @X( a = "A" ) */`}, "X")
+	assert.True(t, ok)
+	assert.Equal(t, "A", ann.Attributes["a"])
+}
+
+func TestUnclosedAnnotationRemainsOnItsOwnLine(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "X",
+			ParamNames: []string{},
+			Validator:  validateOk,
+		},
+	})
+
+	annotations := registry.ResolveAnnotations([]string{`// @X( a = "A"`, `// @X( b = "B" )`})
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, "B", annotations[0].Attributes["b"])
+}
+
 func validateOk(annot Annotation) bool {
 	return true
 }
@@ -106,3 +290,88 @@ func validateOk(annot Annotation) bool {
 func validateError(annot Annotation) bool {
 	return false
 }
+
+func TestAttachAnnotations(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "Validate",
+			ParamNames: []string{"min"},
+			Validator:  validateOk,
+		},
+	})
+
+	parsedSources := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Name: "Person",
+				Fields: []model.Field{
+					{Name: "Age", DocLines: []string{`// @Validate(min="1")`}},
+					{Name: "Name", CommentLines: []string{`// @Validate(min="3")`}},
+					{Name: "Nickname", DocLines: []string{`// @Unknown()`}},
+				},
+				PromotedFields: []model.Field{
+					{Name: "Street", DocLines: []string{`// @Validate()`}},
+				},
+				DocLines: []string{`// @Validate(min="2")`},
+				Operations: []*model.Operation{
+					{Name: "Rename", DocLines: []string{`// @Validate(min="4")`}},
+				},
+			},
+		},
+		Operations: []model.Operation{
+			{Name: "Rename", DocLines: []string{`// @Validate(min="4")`}},
+		},
+		Enums: []model.Enum{
+			{Name: "Color", DocLines: []string{`// @Validate(min="5")`}},
+		},
+	}
+	AttachAnnotations(registry, &parsedSources)
+
+	fields := parsedSources.Structs[0].Fields
+	ann, ok := fields[0].GetAnnotation("Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "1", ann.Attributes["min"])
+	ann, ok = fields[1].GetAnnotation("Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "3", ann.Attributes["min"])
+	assert.NotNil(t, fields[2].Annotations)
+	assert.Empty(t, fields[2].Annotations)
+	assert.True(t, parsedSources.Structs[0].PromotedFields[0].HasAnnotation("Validate"))
+
+	mStruct := parsedSources.Structs[0]
+	ann, ok = registry.FindAnnotation(mStruct.Annotations, nil, "Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "2", ann.Attributes["min"])
+	ann, ok = registry.FindAnnotation(mStruct.Operations[0].Annotations, nil, "Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "4", ann.Attributes["min"])
+	assert.Len(t, parsedSources.Operations[0].Annotations, 1)
+	ann, ok = registry.FindAnnotation(parsedSources.Enums[0].Annotations, nil, "Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "5", ann.Attributes["min"])
+
+	_, ok = registry.FindAnnotation(fields[2].Annotations, fields[2].DocLines, "Validate")
+	assert.False(t, ok)
+}
+
+func TestFindAnnotationFallsBackToDocLines(t *testing.T) {
+	registry := NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "Validate",
+			ParamNames: []string{"min"},
+			Validator:  validateOk,
+		},
+	})
+
+	ann, ok := registry.FindAnnotation(nil, []string{`// @Validate(min="1")`}, "Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "1", ann.Attributes["min"])
+
+	// like after a json round-trip, that does not keep empty annotations apart from missing ones
+	ann, ok = registry.FindAnnotation([]Annotation{}, []string{`// @Validate(min="1")`}, "Validate")
+	assert.True(t, ok)
+	assert.Equal(t, "1", ann.Attributes["min"])
+
+	_, ok = registry.FindAnnotation([]Annotation{{Name: "Other"}}, []string{`// @Other()`}, "Validate")
+	assert.False(t, ok)
+}
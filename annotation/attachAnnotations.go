@@ -0,0 +1,44 @@
+package annotation
+
+import "github.com/MarcGrol/golangAnnotations/model"
+
+// AttachAnnotations resolves the annotations of the structs, operations, enums and struct-fields once, and
+// attaches them to the model: the field-annotations can also be in the comment, example: Age int // @Validate(min="1").
+// The annotations of the package-doc provide the defaults for the attributes of the annotations in the package.
+func AttachAnnotations(registry AnnotationRegister, parsedSources *model.ParsedSources) {
+	defaults := []Annotation{}
+	for idx := range parsedSources.Packages {
+		mPackage := &parsedSources.Packages[idx]
+		mPackage.Annotations = registry.ResolveDefaults(mPackage.DocLines)
+		defaults = append(defaults, mPackage.Annotations...)
+	}
+	registry = registry.WithDefaults(defaults)
+
+	for idx := range parsedSources.Structs {
+		mStruct := &parsedSources.Structs[idx]
+		mStruct.Annotations = registry.ResolveAnnotations(mStruct.DocLines)
+		attachFieldAnnotations(registry, mStruct.Fields)
+		attachFieldAnnotations(registry, mStruct.PromotedFields)
+		for _, mOperation := range mStruct.Operations {
+			mOperation.Annotations = registry.ResolveAnnotations(mOperation.DocLines)
+		}
+		for _, mOperation := range mStruct.PromotedOperations {
+			mOperation.Annotations = registry.ResolveAnnotations(mOperation.DocLines)
+		}
+	}
+	for idx := range parsedSources.Operations {
+		mOperation := &parsedSources.Operations[idx]
+		mOperation.Annotations = registry.ResolveAnnotations(mOperation.DocLines)
+	}
+	for idx := range parsedSources.Enums {
+		mEnum := &parsedSources.Enums[idx]
+		mEnum.Annotations = registry.ResolveAnnotations(mEnum.DocLines)
+	}
+}
+
+func attachFieldAnnotations(registry AnnotationRegister, mFields []model.Field) {
+	for idx := range mFields {
+		lines := append(append([]string{}, mFields[idx].DocLines...), mFields[idx].CommentLines...)
+		mFields[idx].Annotations = registry.ResolveAnnotations(lines)
+	}
+}
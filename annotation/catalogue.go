@@ -0,0 +1,187 @@
+package annotation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	CatalogueText       = "text"
+	CatalogueMarkdown   = "markdown"
+	CatalogueJSONSchema = "json-schema"
+)
+
+// CatalogueEntry is an annotation together with the generator that owns it
+type CatalogueEntry struct {
+	Generator  string
+	Descriptor AnnotationDescriptor
+}
+
+// WriteCatalogue documents the annotations in the given format: text, markdown or json-schema
+func WriteCatalogue(w io.Writer, format string, entries []CatalogueEntry) error {
+	switch format {
+	case CatalogueText:
+		return writeCatalogueText(w, entries)
+	case CatalogueMarkdown:
+		return writeCatalogueMarkdown(w, entries)
+	case CatalogueJSONSchema:
+		return writeCatalogueJSONSchema(w, entries)
+	default:
+		return fmt.Errorf("Unknown catalogue-format %s: use %s, %s or %s", format, CatalogueText, CatalogueMarkdown, CatalogueJSONSchema)
+	}
+}
+
+// paramType returns the type of the parameter: a parameter without type accepts any value, written as string
+func (descriptor AnnotationDescriptor) paramType(name string) ParamType {
+	if paramType, ok := descriptor.ParamTypes[name]; ok {
+		return paramType
+	}
+	return ParamTypeString
+}
+
+func (descriptor AnnotationDescriptor) isRequired(name string) bool {
+	for _, required := range descriptor.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rules describes the rules of the descriptor that apply to a declaration
+func (descriptor AnnotationDescriptor) rules() []string {
+	rules := []string{}
+	if len(descriptor.Targets) > 0 {
+		rules = append(rules, fmt.Sprintf("on %s only", joinTargets(descriptor.Targets)))
+	}
+	if descriptor.Unique {
+		rules = append(rules, "unique")
+	}
+	for _, name := range descriptor.ExclusiveWith {
+		rules = append(rules, fmt.Sprintf("cannot be combined with @%s", name))
+	}
+	for _, name := range descriptor.RequiresEnclosing {
+		rules = append(rules, fmt.Sprintf("requires @%s on the enclosing struct", name))
+	}
+	return rules
+}
+
+func writeCatalogueText(w io.Writer, entries []CatalogueEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for idx, entry := range entries {
+		if idx > 0 {
+			fmt.Fprintln(tw)
+		}
+		descriptor := entry.Descriptor
+		fmt.Fprintf(tw, "@%s (%s)\n", descriptor.Name, entry.Generator)
+		if descriptor.Description != "" {
+			fmt.Fprintf(tw, "    %s\n", descriptor.Description)
+		}
+		if rules := descriptor.rules(); len(rules) > 0 {
+			fmt.Fprintf(tw, "    Rules: %s\n", strings.Join(rules, "; "))
+		}
+		for _, name := range descriptor.ParamNames {
+			required := ""
+			if descriptor.isRequired(name) {
+				required = "required"
+			}
+			fmt.Fprintf(tw, "    %s\t%s\t%s\t%s\n", name, descriptor.paramType(name), required, descriptor.ParamDescriptions[name])
+		}
+	}
+	return tw.Flush()
+}
+
+func writeCatalogueMarkdown(w io.Writer, entries []CatalogueEntry) error {
+	fmt.Fprintf(w, "# Annotations\n")
+	for _, entry := range entries {
+		descriptor := entry.Descriptor
+		fmt.Fprintf(w, "\n## @%s\n\n", descriptor.Name)
+		fmt.Fprintf(w, "Generator: %s\n", entry.Generator)
+		if descriptor.Description != "" {
+			fmt.Fprintf(w, "\n%s\n", descriptor.Description)
+		}
+		if rules := descriptor.rules(); len(rules) > 0 {
+			fmt.Fprintf(w, "\nRules: %s\n", strings.Join(rules, "; "))
+		}
+		if len(descriptor.ParamNames) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n| Parameter | Type | Required | Description |\n")
+		fmt.Fprintf(w, "|-----------|------|----------|-------------|\n")
+		for _, name := range descriptor.ParamNames {
+			required := "no"
+			if descriptor.isRequired(name) {
+				required = "yes"
+			}
+			description := strings.Replace(descriptor.ParamDescriptions[name], "|", "\\|", -1)
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", name, descriptor.paramType(name), required, description)
+		}
+	}
+	return nil
+}
+
+func writeCatalogueJSONSchema(w io.Writer, entries []CatalogueEntry) error {
+	definitions := map[string]interface{}{}
+	refs := []interface{}{}
+	for _, entry := range entries {
+		descriptor := entry.Descriptor
+		properties := map[string]interface{}{}
+		for _, name := range descriptor.ParamNames {
+			property := jsonSchemaType(descriptor.paramType(name))
+			if description := descriptor.ParamDescriptions[name]; description != "" {
+				property["description"] = description
+			}
+			properties[name] = property
+		}
+		definition := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+			"x-generator":          entry.Generator,
+		}
+		if descriptor.Description != "" {
+			definition["description"] = descriptor.Description
+		}
+		if len(descriptor.Required) > 0 {
+			definition["required"] = descriptor.Required
+		}
+		if rules := descriptor.rules(); len(rules) > 0 {
+			definition["x-rules"] = rules
+		}
+		definitions[descriptor.Name] = definition
+		refs = append(refs, map[string]interface{}{"$ref": "#/$defs/" + descriptor.Name})
+	}
+	schema := map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "golangAnnotations",
+		"description": "Parameters of the annotations, by annotation-name",
+		"$defs":       definitions,
+		"anyOf":       refs,
+	}
+	marshalled, err := json.MarshalIndent(schema, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", marshalled)
+	return err
+}
+
+func jsonSchemaType(paramType ParamType) map[string]interface{} {
+	switch paramType {
+	case ParamTypeBool:
+		return map[string]interface{}{"type": "boolean"}
+	case ParamTypeInt:
+		return map[string]interface{}{"type": "integer"}
+	case ParamTypeFloat:
+		return map[string]interface{}{"type": "number"}
+	case ParamTypeList:
+		return map[string]interface{}{"type": "array"}
+	case ParamTypeAnnotation:
+		return map[string]interface{}{"type": "object"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
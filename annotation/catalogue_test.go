@@ -0,0 +1,93 @@
+package annotation
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func catalogueEntries() []CatalogueEntry {
+	return []CatalogueEntry{
+		{
+			Generator: "rest",
+			Descriptor: AnnotationDescriptor{
+				Name:        "RestOperation",
+				Description: "Exposes the method as http-endpoint",
+				ParamNames:  []string{"method", "form"},
+				ParamTypes:  map[string]ParamType{"form": ParamTypeBool},
+				ParamDescriptions: map[string]string{
+					"method": "Http-method",
+					"form":   "Read the input from a form",
+				},
+				Required:  []string{"method"},
+				Validator: validateOk,
+			},
+		},
+		{
+			Generator: "json-helpers",
+			Descriptor: AnnotationDescriptor{
+				Name:       "JsonStruct",
+				ParamNames: []string{},
+				Validator:  validateOk,
+			},
+		},
+	}
+}
+
+func TestCatalogueText(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteCatalogue(buf, CatalogueText, catalogueEntries())
+	assert.NoError(t, err)
+	assert.Equal(t, `@RestOperation (rest)
+    Exposes the method as http-endpoint
+    method  string  required  Http-method
+    form    bool              Read the input from a form
+
+@JsonStruct (json-helpers)
+`, buf.String())
+}
+
+func TestCatalogueMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteCatalogue(buf, CatalogueMarkdown, catalogueEntries())
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "## @RestOperation\n\nGenerator: rest\n\nExposes the method as http-endpoint\n")
+	assert.Contains(t, buf.String(), "| method | string | yes | Http-method |\n")
+	assert.Contains(t, buf.String(), "| form | bool | no | Read the input from a form |\n")
+	assert.Contains(t, buf.String(), "## @JsonStruct\n\nGenerator: json-helpers\n")
+}
+
+func TestCatalogueJSONSchema(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := WriteCatalogue(buf, CatalogueJSONSchema, catalogueEntries())
+	assert.NoError(t, err)
+
+	schema := struct {
+		Defs map[string]struct {
+			Description string                       `json:"description"`
+			Generator   string                       `json:"x-generator"`
+			Properties  map[string]map[string]string `json:"properties"`
+			Required    []string                     `json:"required"`
+		} `json:"$defs"`
+		AnyOf []map[string]string `json:"anyOf"`
+	}{}
+	err = json.Unmarshal(buf.Bytes(), &schema)
+	assert.NoError(t, err)
+
+	restOperation := schema.Defs["RestOperation"]
+	assert.Equal(t, "Exposes the method as http-endpoint", restOperation.Description)
+	assert.Equal(t, "rest", restOperation.Generator)
+	assert.Equal(t, "string", restOperation.Properties["method"]["type"])
+	assert.Equal(t, "boolean", restOperation.Properties["form"]["type"])
+	assert.Equal(t, "Read the input from a form", restOperation.Properties["form"]["description"])
+	assert.Equal(t, []string{"method"}, restOperation.Required)
+	assert.Empty(t, schema.Defs["JsonStruct"].Properties)
+	assert.Equal(t, []map[string]string{{"$ref": "#/$defs/RestOperation"}, {"$ref": "#/$defs/JsonStruct"}}, schema.AnyOf)
+}
+
+func TestCatalogueUnknownFormat(t *testing.T) {
+	err := WriteCatalogue(&bytes.Buffer{}, "html", catalogueEntries())
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package annotation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ResolveDefaults resolves the annotations of a package-doc, example: @RestService( credentials = "all" ).
+// These provide defaults for the attributes of the annotations in the package, so they need not be complete:
+// required parameters may be missing and the validator does not apply.
+func (ar *annotationRegistry) ResolveDefaults(annotationDocline []string) []Annotation {
+	defaults := []Annotation{}
+	for _, statement := range resolveStatements(annotationDocline) {
+		ann, err := parseAnnotation(strings.TrimSpace(statement.text))
+		if err != nil {
+			continue
+		}
+		descriptor, ok := ar.findDescriptor(ann.Name)
+		if !ok {
+			continue
+		}
+		// an invalid value is reported by ValidateDefaults
+		ann, _ = descriptor.convertValues(ann)
+		defaults = append(defaults, ann)
+	}
+	return defaults
+}
+
+// ValidateDefaults reports the problems of an annotation of a package-doc: like ValidateAnnotation,
+// but without requiring parameters
+func (ar *annotationRegistry) ValidateDefaults(annotationDocline string) []error {
+	ann, parseErr := parseAnnotation(strings.TrimSpace(annotationDocline))
+	if ann.Name == "" {
+		return nil
+	}
+	descriptor, ok := ar.findDescriptor(ann.Name)
+	if !ok {
+		return ar.ValidateAnnotation(annotationDocline)
+	}
+	if parseErr != nil {
+		return []error{fmt.Errorf("Invalid syntax of annotation @%s", ann.Name)}
+	}
+	errs := []error{}
+	for _, name := range sortedAttributeNames(ann) {
+		if !containsParam(descriptor.ParamNames, name) {
+			errs = append(errs, unknownParamError(descriptor, name))
+		}
+	}
+	_, err := descriptor.convertValues(ann)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// WithDefaults returns a registry that completes the annotations it resolves with the attributes
+// of the defaults with the same name: attributes of the annotation itself take precedence
+func (ar *annotationRegistry) WithDefaults(defaults []Annotation) AnnotationRegister {
+	return &annotationRegistry{
+		descriptors: ar.descriptors,
+		defaults:    append(append([]Annotation{}, ar.defaults...), defaults...),
+	}
+}
+
+func (ar *annotationRegistry) applyDefaults(ann Annotation) Annotation {
+	if len(ar.defaults) == 0 {
+		return ann
+	}
+	completed := Annotation{
+		Name:       ann.Name,
+		Attributes: map[string]string{},
+		Values:     map[string]model.AnnotationValue{},
+	}
+	for name, text := range ann.Attributes {
+		completed.Attributes[name] = text
+	}
+	for name, value := range ann.Values {
+		completed.Values[name] = value
+	}
+	for _, defaultAnn := range ar.defaults {
+		if defaultAnn.Name != ann.Name {
+			continue
+		}
+		for name, text := range defaultAnn.Attributes {
+			if _, ok := completed.Attributes[name]; !ok {
+				completed.Attributes[name] = text
+				if value, ok := defaultAnn.Values[name]; ok {
+					completed.Values[name] = value
+				}
+			}
+		}
+	}
+	return completed
+}
@@ -0,0 +1,107 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultsRegistry() AnnotationRegister {
+	return NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "Event",
+			ParamNames: []string{"aggregate", "istransient"},
+			ParamTypes: map[string]ParamType{"istransient": ParamTypeBool},
+			Required:   []string{"aggregate"},
+			Validator: func(annot Annotation) bool {
+				return annot.Attributes["aggregate"] != ""
+			},
+		},
+	})
+}
+
+func TestResolveDefaults(t *testing.T) {
+	defaults := defaultsRegistry().ResolveDefaults([]string{
+		`// Package tour has default annotations`,
+		`// @Event( istransient = "true" )`,
+		`// @Unknown( a = "A" )`,
+	})
+	assert.Len(t, defaults, 1)
+	assert.Equal(t, "Event", defaults[0].Name)
+	assert.True(t, defaults[0].GetBool("istransient"))
+}
+
+func TestWithDefaults(t *testing.T) {
+	registry := defaultsRegistry().WithDefaults([]Annotation{
+		{
+			Name:       "Event",
+			Attributes: map[string]string{"aggregate": "Tour", "istransient": "true"},
+			Values: map[string]model.AnnotationValue{
+				"aggregate":   {Kind: model.AnnotationValueString, Text: "Tour"},
+				"istransient": {Kind: model.AnnotationValueBool, Text: "true"},
+			},
+		},
+	})
+
+	ann, ok := registry.ResolveAnnotation(`// @Event()`)
+	assert.True(t, ok)
+	assert.Equal(t, "Tour", ann.Attributes["aggregate"])
+	assert.True(t, ann.GetBool("istransient"))
+
+	ann, ok = registry.ResolveAnnotation(`// @Event( aggregate = "Gambler", istransient = false )`)
+	assert.True(t, ok)
+	assert.Equal(t, "Gambler", ann.Attributes["aggregate"])
+	assert.False(t, ann.GetBool("istransient"))
+
+	_, ok = defaultsRegistry().ResolveAnnotation(`// @Event()`)
+	assert.False(t, ok)
+}
+
+func TestAttachAnnotationsWithPackageDefaults(t *testing.T) {
+	parsedSources := model.ParsedSources{
+		Packages: []model.Package{
+			{Name: "tour", DocLines: []string{`// @Event( aggregate = "Tour" )`}},
+		},
+		Structs: []model.Struct{
+			{Name: "TourCreated", DocLines: []string{`// @Event()`}},
+			{Name: "GamblerCreated", DocLines: []string{`// @Event( aggregate = "Gambler" )`}},
+		},
+	}
+	registry := defaultsRegistry()
+	AttachAnnotations(registry, &parsedSources)
+
+	assert.Len(t, parsedSources.Packages[0].Annotations, 1)
+	ann, ok := registry.FindAnnotation(parsedSources.Structs[0].Annotations, nil, "Event")
+	assert.True(t, ok)
+	assert.Equal(t, "Tour", ann.Attributes["aggregate"])
+	ann, ok = registry.FindAnnotation(parsedSources.Structs[1].Annotations, nil, "Event")
+	assert.True(t, ok)
+	assert.Equal(t, "Gambler", ann.Attributes["aggregate"])
+}
+
+func TestValidateAnnotationsWithPackageDefaults(t *testing.T) {
+	parsedSources := model.ParsedSources{
+		Packages: []model.Package{
+			{
+				Name:     "tour",
+				Position: model.Position{Filename: "doc.go", Line: 3},
+				DocLines: []string{`// @Event( aggregate = "Tour", istransent = true )`, `//`},
+			},
+		},
+		Structs: []model.Struct{
+			{
+				Name:     "TourCreated",
+				Position: model.Position{Filename: "events.go", Line: 4},
+				DocLines: []string{`// @Event()`},
+			},
+		},
+	}
+
+	problems := ValidateAnnotations(defaultsRegistry(), parsedSources)
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{"doc.go:1: Unknown parameter istransent of annotation @Event: did you mean istransient?"}, messages)
+}
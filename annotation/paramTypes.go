@@ -0,0 +1,85 @@
+package annotation
+
+import (
+	"fmt"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// convertValues converts the values of the annotation to the types of the parameters: values
+// written as string remain valid, example: @X(flag = "true") for a bool-parameter. A value that cannot
+// be converted is kept as written and reported in the error.
+func (descriptor AnnotationDescriptor) convertValues(ann Annotation) (Annotation, error) {
+	if len(descriptor.ParamTypes) == 0 {
+		return ann, nil
+	}
+	converted := Annotation{
+		Name:       ann.Name,
+		Attributes: map[string]string{},
+		Values:     map[string]model.AnnotationValue{},
+	}
+	for name, text := range ann.Attributes {
+		converted.Attributes[name] = text
+	}
+	var firstErr error
+	for _, name := range sortedAttributeNames(ann) {
+		value, ok := ann.Values[name]
+		if !ok {
+			continue
+		}
+		paramType, ok := descriptor.ParamTypes[name]
+		if ok {
+			convertedValue, err := convertValue(value, paramType)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("Invalid value for parameter %s of annotation @%s: %v", name, ann.Name, err)
+				}
+				convertedValue = value
+			}
+			value = convertedValue
+		}
+		converted.Values[name] = value
+		converted.Attributes[name] = value.String()
+	}
+	return converted, firstErr
+}
+
+func convertValue(value model.AnnotationValue, paramType ParamType) (model.AnnotationValue, error) {
+	switch paramType {
+	case ParamTypeString:
+		if value.Kind == model.AnnotationValueList || value.Kind == model.AnnotationValueAnnotation {
+			break
+		}
+		return model.AnnotationValue{Kind: model.AnnotationValueString, Text: value.Text}, nil
+	case ParamTypeBool:
+		if b, ok := value.Bool(); ok {
+			return model.AnnotationValue{Kind: model.AnnotationValueBool, Text: fmt.Sprintf("%v", b)}, nil
+		}
+	case ParamTypeInt:
+		if i, ok := value.Int(); ok {
+			return model.AnnotationValue{Kind: model.AnnotationValueInt, Text: fmt.Sprintf("%d", i)}, nil
+		}
+	case ParamTypeFloat:
+		if _, ok := value.Float(); ok {
+			return model.AnnotationValue{Kind: model.AnnotationValueFloat, Text: value.Text}, nil
+		}
+	case ParamTypeList:
+		if value.Kind == model.AnnotationValueList {
+			return value, nil
+		}
+		if value.Kind == model.AnnotationValueString {
+			list := model.AnnotationValue{Kind: model.AnnotationValueList, List: []model.AnnotationValue{}}
+			for _, s := range value.Strings() {
+				list.List = append(list.List, model.AnnotationValue{Kind: model.AnnotationValueString, Text: s})
+			}
+			return list, nil
+		}
+	case ParamTypeAnnotation:
+		if value.Kind == model.AnnotationValueAnnotation {
+			return value, nil
+		}
+	default:
+		return value, nil
+	}
+	return value, fmt.Errorf("%s-value %q is not a %s", value.Kind, value.String(), paramType)
+}
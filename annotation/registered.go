@@ -0,0 +1,38 @@
+package annotation
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	registeredMutex sync.Mutex
+	registered      = map[string][]AnnotationDescriptor{}
+)
+
+// RegisterDescriptors makes the annotations of a generator known to the parser, that attaches them to the model:
+// registering again under the same owner replaces the descriptors
+func RegisterDescriptors(owner string, descriptors []AnnotationDescriptor) {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+
+	registered[owner] = descriptors
+}
+
+// RegisteredDescriptors returns the descriptors of all owners, sorted by owner
+func RegisteredDescriptors() []AnnotationDescriptor {
+	registeredMutex.Lock()
+	defer registeredMutex.Unlock()
+
+	owners := []string{}
+	for owner := range registered {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	descriptors := []AnnotationDescriptor{}
+	for _, owner := range owners {
+		descriptors = append(descriptors, registered[owner]...)
+	}
+	return descriptors
+}
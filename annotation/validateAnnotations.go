@@ -0,0 +1,249 @@
+package annotation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ValidateAnnotation reports the problems of an annotation that the registry would silently ignore:
+// unknown parameters, missing required parameters, invalid values and near-misses of annotation-names.
+// Lines without annotation, or with an annotation unknown to the registry, have no problems.
+func (ar *annotationRegistry) ValidateAnnotation(annotationDocline string) []error {
+	ann, parseErr := parseAnnotation(strings.TrimSpace(annotationDocline))
+	if ann.Name == "" {
+		return nil
+	}
+
+	descriptor, ok := ar.findDescriptor(ann.Name)
+	if !ok {
+		if suggestion, ok := nearMiss(ann.Name, ar.descriptorNames()); ok {
+			return []error{fmt.Errorf("Unknown annotation @%s: did you mean @%s?", ann.Name, suggestion)}
+		}
+		return nil
+	}
+	if parseErr != nil {
+		return []error{fmt.Errorf("Invalid syntax of annotation @%s", ann.Name)}
+	}
+
+	errs := []error{}
+	for _, name := range sortedAttributeNames(ann) {
+		if !containsParam(descriptor.ParamNames, name) {
+			errs = append(errs, unknownParamError(descriptor, name))
+		}
+	}
+	// a package-level default can provide a required parameter
+	completed := ar.applyDefaults(ann)
+	for _, name := range descriptor.Required {
+		if _, ok := completed.Attributes[strings.ToLower(name)]; !ok {
+			errs = append(errs, fmt.Errorf("Missing parameter %s of annotation @%s", name, ann.Name))
+		}
+	}
+	converted, err := descriptor.convertValues(completed)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 && !descriptor.Validator(converted) {
+		errs = append(errs, fmt.Errorf("Invalid annotation @%s", ann.Name))
+	}
+	return errs
+}
+
+func (ar *annotationRegistry) findDescriptor(name string) (AnnotationDescriptor, bool) {
+	for _, descriptor := range ar.descriptors {
+		if descriptor.Name == name {
+			return descriptor, true
+		}
+	}
+	return AnnotationDescriptor{}, false
+}
+
+func (ar *annotationRegistry) descriptorNames() []string {
+	names := []string{}
+	for _, descriptor := range ar.descriptors {
+		names = append(names, descriptor.Name)
+	}
+	return names
+}
+
+func sortedAttributeNames(ann Annotation) []string {
+	names := []string{}
+	for name := range ann.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func containsParam(paramNames []string, name string) bool {
+	for _, paramName := range paramNames {
+		if strings.ToLower(paramName) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownParamError(descriptor AnnotationDescriptor, name string) error {
+	if suggestion, ok := nearMiss(name, descriptor.ParamNames); ok {
+		return fmt.Errorf("Unknown parameter %s of annotation @%s: did you mean %s?", name, descriptor.Name, suggestion)
+	}
+	if len(descriptor.ParamNames) == 0 {
+		return fmt.Errorf("Unknown parameter %s of annotation @%s: it has no parameters", name, descriptor.Name)
+	}
+	return fmt.Errorf("Unknown parameter %s of annotation @%s: expected one of %s", name, descriptor.Name, strings.Join(descriptor.ParamNames, ", "))
+}
+
+// nearMiss returns the candidate that is most likely meant by the misspelled name
+func nearMiss(name string, candidates []string) (string, bool) {
+	best := ""
+	bestDistance := maxDistance(name) + 1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best, best != ""
+}
+
+func maxDistance(name string) int {
+	if len(name) <= 5 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the number of single-character insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minOf(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minOf(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minOf(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// ValidateAnnotations validates the annotations of the packages, structs, operations, enums and struct-fields,
+// and returns the problems as errors that point to the offending line
+func ValidateAnnotations(registry AnnotationRegister, parsedSources model.ParsedSources) []diagnostics.Diagnostic {
+	problems := []diagnostics.Diagnostic{}
+	defaults := []Annotation{}
+	for _, mPackage := range parsedSources.Packages {
+		problems = append(problems, validateStatements(registry.ValidateDefaults, mPackage.Position, mPackage.DocLines, docLineNumbers(mPackage.Position, mPackage.DocLines, mPackage.DocPositions))...)
+		defaults = append(defaults, registry.ResolveDefaults(mPackage.DocLines)...)
+	}
+	registry = registry.WithDefaults(defaults)
+
+	for _, mStruct := range parsedSources.Structs {
+		problems = append(problems, validateDocLines(registry, mStruct.Position, mStruct.DocLines, mStruct.DocPositions)...)
+		for _, mField := range mStruct.Fields {
+			problems = append(problems, validateDocLines(registry, mField.Position, mField.DocLines, mField.DocPositions)...)
+			problems = append(problems, validateCommentLines(registry, mField.Position, mField.CommentLines, mField.CommentPositions)...)
+		}
+	}
+	for _, mOperation := range parsedSources.Operations {
+		problems = append(problems, validateDocLines(registry, mOperation.Position, mOperation.DocLines, mOperation.DocPositions)...)
+	}
+	for _, mEnum := range parsedSources.Enums {
+		problems = append(problems, validateDocLines(registry, mEnum.Position, mEnum.DocLines, mEnum.DocPositions)...)
+	}
+	return problems
+}
+
+func validateDocLines(registry AnnotationRegister, position model.Position, docLines []string, docPositions []model.Position) []diagnostics.Diagnostic {
+	return validateStatements(registry.ValidateAnnotation, position, docLines, docLineNumbers(position, docLines, docPositions))
+}
+
+func validateCommentLines(registry AnnotationRegister, position model.Position, commentLines []string, commentPositions []model.Position) []diagnostics.Diagnostic {
+	return validateStatements(registry.ValidateAnnotation, position, commentLines, commentLineNumbers(position, commentLines, commentPositions))
+}
+
+// docLineNumbers returns the line in the file of each line of splitCommentLines: without positions of the
+// comments, like for a model that was built by hand, the doc-lines are assumed to directly precede the element
+func docLineNumbers(position model.Position, docLines []string, docPositions []model.Position) []int {
+	if lineNumbers, ok := lineNumbersOfComments(docLines, docPositions); ok {
+		return lineNumbers
+	}
+	return lineNumbersFrom(position, len(splitCommentLines(docLines)), docLines)
+}
+
+// commentLineNumbers is like docLineNumbers, but assumes the comment-lines start at the line of the element
+func commentLineNumbers(position model.Position, commentLines []string, commentPositions []model.Position) []int {
+	if lineNumbers, ok := lineNumbersOfComments(commentLines, commentPositions); ok {
+		return lineNumbers
+	}
+	return lineNumbersFrom(position, 0, commentLines)
+}
+
+func lineNumbersOfComments(comments []string, positions []model.Position) ([]int, bool) {
+	if len(positions) != len(comments) {
+		return nil, false
+	}
+	lineNumbers := []int{}
+	for idx, comment := range comments {
+		if !positions[idx].IsValid() {
+			return nil, false
+		}
+		for offset := range splitCommentLines([]string{comment}) {
+			lineNumbers = append(lineNumbers, positions[idx].Line+offset)
+		}
+	}
+	return lineNumbers, true
+}
+
+func lineNumbersFrom(position model.Position, linesBefore int, comments []string) []int {
+	if !position.IsValid() {
+		return nil
+	}
+	lineNumbers := []int{}
+	for offset := range splitCommentLines(comments) {
+		lineNumbers = append(lineNumbers, position.Line-linesBefore+offset)
+	}
+	return lineNumbers
+}
+
+func validateStatements(validate func(string) []error, position model.Position, lines []string, lineNumbers []int) []diagnostics.Diagnostic {
+	problems := []diagnostics.Diagnostic{}
+	for _, statement := range resolveStatements(lines) {
+		linePosition := model.Position{Filename: position.Filename}
+		if statement.line < len(lineNumbers) {
+			linePosition.Line = lineNumbers[statement.line]
+		}
+		for _, err := range validate(statement.text) {
+			problems = append(problems, diagnostics.Errorf(linePosition, "%s", err))
+		}
+	}
+	return problems
+}
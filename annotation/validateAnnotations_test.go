@@ -0,0 +1,139 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func validatingRegistry() AnnotationRegister {
+	return NewRegistry([]AnnotationDescriptor{
+		{
+			Name:       "RestOperation",
+			ParamNames: []string{"method", "path", "form"},
+			ParamTypes: map[string]ParamType{"form": ParamTypeBool},
+			Required:   []string{"method"},
+			Validator:  validateOk,
+		},
+		{
+			Name:       "Event",
+			ParamNames: []string{"aggregate"},
+			Required:   []string{"aggregate"},
+			Validator:  validateOk,
+		},
+	})
+}
+
+func validationMessages(errs []error) []string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestValidateCorrectAnnotation(t *testing.T) {
+	assert.Empty(t, validatingRegistry().ValidateAnnotation(`// @RestOperation( method = "GET", form = true )`))
+}
+
+func TestValidateIgnoresOtherLines(t *testing.T) {
+	registry := validatingRegistry()
+	assert.Empty(t, registry.ValidateAnnotation(`// Doit does something, mail info@example.com`))
+	assert.Empty(t, registry.ValidateAnnotation(`// @Deprecated( reason = "old" )`))
+}
+
+func TestValidateUnknownParameter(t *testing.T) {
+	errs := validatingRegistry().ValidateAnnotation(`// @RestOperation( mehtod = "GET" )`)
+	assert.Equal(t, []string{
+		"Unknown parameter mehtod of annotation @RestOperation: did you mean method?",
+		"Missing parameter method of annotation @RestOperation",
+	}, validationMessages(errs))
+
+	errs = validatingRegistry().ValidateAnnotation(`// @Event( aggregate = "A", other = "B" )`)
+	assert.Equal(t, []string{"Unknown parameter other of annotation @Event: expected one of aggregate"}, validationMessages(errs))
+}
+
+func TestValidateNearMissAnnotationName(t *testing.T) {
+	registry := validatingRegistry()
+	errs := registry.ValidateAnnotation(`// @RestOperaton( method = "GET" )`)
+	assert.Equal(t, []string{"Unknown annotation @RestOperaton: did you mean @RestOperation?"}, validationMessages(errs))
+
+	errs = registry.ValidateAnnotation(`// @event( aggregate = "A" )`)
+	assert.Equal(t, []string{"Unknown annotation @event: did you mean @Event?"}, validationMessages(errs))
+}
+
+func TestValidateInvalidValue(t *testing.T) {
+	errs := validatingRegistry().ValidateAnnotation(`// @RestOperation( method = "GET", form = "maybe" )`)
+	assert.Equal(t, []string{`Invalid value for parameter form of annotation @RestOperation: string-value "maybe" is not a bool`}, validationMessages(errs))
+}
+
+func TestValidateInvalidSyntax(t *testing.T) {
+	errs := validatingRegistry().ValidateAnnotation(`// @RestOperation( method = "GET"`)
+	assert.Equal(t, []string{"Invalid syntax of annotation @RestOperation"}, validationMessages(errs))
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	parsedSources := model.ParsedSources{
+		Structs: []model.Struct{
+			{
+				Name:     "Created",
+				Position: model.Position{Filename: "events.go", Line: 12, Column: 6},
+				DocLines: []string{`// Created is an event`, `// @Event( agregate = "A" )`},
+				Fields: []model.Field{
+					{
+						Name:         "Name",
+						Position:     model.Position{Filename: "events.go", Line: 13, Column: 2},
+						CommentLines: []string{`// @Evnt( aggregate = "A" )`},
+					},
+				},
+			},
+		},
+		Operations: []model.Operation{
+			{
+				Name:     "doit",
+				Position: model.Position{Filename: "service.go", Line: 4, Column: 6},
+				DocLines: []string{`// @RestOperation( method = "GET" )`},
+			},
+		},
+	}
+
+	problems := ValidateAnnotations(validatingRegistry(), parsedSources)
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		"events.go:11: Unknown parameter agregate of annotation @Event: did you mean aggregate?",
+		"events.go:11: Missing parameter aggregate of annotation @Event",
+		"events.go:13: Unknown annotation @Evnt: did you mean @Event?",
+	}, messages)
+}
+
+func TestValidateMultiLineAnnotation(t *testing.T) {
+	parsedSources := model.ParsedSources{
+		Operations: []model.Operation{
+			{
+				Name:     "doit",
+				Position: model.Position{Filename: "service.go", Line: 10, Column: 6},
+				DocLines: []string{
+					`// @RestOperation( method = "GET",`,
+					`//     path = "/doit", form = "maybe" )`,
+					`// @Event(`,
+					`//     agregate = "A" )`,
+				},
+			},
+		},
+	}
+
+	problems := ValidateAnnotations(validatingRegistry(), parsedSources)
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		`service.go:6: Invalid value for parameter form of annotation @RestOperation: string-value "maybe" is not a bool`,
+		"service.go:8: Unknown parameter agregate of annotation @Event: did you mean aggregate?",
+		"service.go:8: Missing parameter aggregate of annotation @Event",
+	}, messages)
+}
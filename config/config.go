@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Filename is the name of the project-config: it is looked up in the working directory and its parents,
+// up to the root of the module or repository
+const Filename = "golangAnnotations.json"
+
+// rootMarkers mark the root of a module or repository: a project-config above it belongs to another project
+var rootMarkers = []string{"go.mod", ".git"}
+
+// Config is the project-config of golangAnnotations
+type Config struct {
+	Plugins     []Plugin `json:"plugins,omitempty"`
+	TemplateDir string   `json:"templateDir,omitempty"`
+}
+
+// Plugin is an external generator: a command that receives the parsed sources as json on stdin
+// and returns the files to write as json on stdout
+type Plugin struct {
+	Name        string       `json:"name"`
+	Command     string       `json:"command"`
+	Args        []string     `json:"args,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation declares an annotation of a plugin, so that it is validated and attached like the built-in ones
+type Annotation struct {
+	Name              string            `json:"name"`
+	Description       string            `json:"description,omitempty"`
+	Params            []string          `json:"params,omitempty"`
+	ParamTypes        map[string]string `json:"paramTypes,omitempty"`
+	ParamDescriptions map[string]string `json:"paramDescriptions,omitempty"`
+	Required          []string          `json:"required,omitempty"`
+	Unique            bool              `json:"unique,omitempty"`
+	ExclusiveWith     []string          `json:"exclusiveWith,omitempty"`
+	RequiresEnclosing []string          `json:"requiresEnclosing,omitempty"`
+	Targets           []string          `json:"targets,omitempty"` // struct, field, operation or enum: any when empty
+}
+
+var validTargets = map[string]bool{
+	"struct":    true,
+	"field":     true,
+	"operation": true,
+	"enum":      true,
+}
+
+var validParamTypes = map[string]bool{
+	model.AnnotationValueString:     true,
+	model.AnnotationValueBool:       true,
+	model.AnnotationValueInt:        true,
+	model.AnnotationValueFloat:      true,
+	model.AnnotationValueList:       true,
+	model.AnnotationValueAnnotation: true,
+}
+
+// Find looks up the project-config in dir and its parents, up to the root of the module or repository
+func Find(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		filename := filepath.Join(dir, Filename)
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filename, true
+		}
+		if isRoot(dir) {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func isRoot(dir string) bool {
+	for _, marker := range rootMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the project-config: a template-dir or command of a plugin given as relative path is relative to the config-file
+func Load(filename string) (Config, error) {
+	config := Config{}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return config, fmt.Errorf("Error reading config %s:%s", filename, err)
+	}
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return config, fmt.Errorf("Error parsing config %s:%s", filename, err)
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return config, err
+	}
+	if config.TemplateDir != "" && !filepath.IsAbs(config.TemplateDir) {
+		config.TemplateDir = filepath.Join(dir, config.TemplateDir)
+	}
+
+	names := map[string]bool{}
+	for idx, plugin := range config.Plugins {
+		if plugin.Name == "" || plugin.Command == "" {
+			return config, fmt.Errorf("Invalid config %s: plugin %d needs a name and a command", filename, idx+1)
+		}
+		if names[plugin.Name] {
+			return config, fmt.Errorf("Invalid config %s: plugin %s is declared twice", filename, plugin.Name)
+		}
+		names[plugin.Name] = true
+		for _, declared := range plugin.Annotations {
+			for _, target := range declared.Targets {
+				if !validTargets[target] {
+					return config, fmt.Errorf("Invalid config %s: annotation @%s of plugin %s has unknown target %s", filename, declared.Name, plugin.Name, target)
+				}
+			}
+			for param, paramType := range declared.ParamTypes {
+				if !validParamTypes[paramType] {
+					return config, fmt.Errorf("Invalid config %s: parameter %s of annotation @%s of plugin %s has unknown type %s", filename, param, declared.Name, plugin.Name, paramType)
+				}
+			}
+		}
+		if strings.ContainsRune(plugin.Command, filepath.Separator) && !filepath.IsAbs(plugin.Command) {
+			config.Plugins[idx].Command = filepath.Join(dir, plugin.Command)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAndLoad(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(projectDir)
+
+	err = ioutil.WriteFile(filepath.Join(projectDir, Filename), []byte(`{
+		"templateDir": "templates",
+		"plugins": [
+			{
+				"name": "graphql",
+				"command": "./tools/graphql-gen",
+				"args": ["-v"],
+				"annotations": [{"name": "GraphqlType", "params": ["name"], "paramTypes": {"name": "string"}}]
+			},
+			{"name": "docs", "command": "docs-gen"}
+		]
+	}`), 0644)
+	assert.NoError(t, err)
+	packageDir := filepath.Join(projectDir, "a", "b")
+	err = os.MkdirAll(packageDir, 0777)
+	assert.NoError(t, err)
+
+	filename, ok := Find(packageDir)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(projectDir, Filename), filename)
+
+	config, err := Load(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectDir, "templates"), config.TemplateDir)
+	assert.Len(t, config.Plugins, 2)
+	assert.Equal(t, filepath.Join(projectDir, "tools", "graphql-gen"), config.Plugins[0].Command)
+	assert.Equal(t, []string{"-v"}, config.Plugins[0].Args)
+	assert.Equal(t, "GraphqlType", config.Plugins[0].Annotations[0].Name)
+	assert.Equal(t, "docs-gen", config.Plugins[1].Command)
+}
+
+func TestFindStopsAtModuleRoot(t *testing.T) {
+	outerDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outerDir)
+
+	err = ioutil.WriteFile(filepath.Join(outerDir, Filename), []byte(`{}`), 0644)
+	assert.NoError(t, err)
+	moduleDir := filepath.Join(outerDir, "module")
+	packageDir := filepath.Join(moduleDir, "a")
+	err = os.MkdirAll(packageDir, 0777)
+	assert.NoError(t, err)
+
+	// without a root in between, the config of the outer directory is found
+	filename, ok := Find(packageDir)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(outerDir, Filename), filename)
+
+	for _, marker := range []string{"go.mod", ".git"} {
+		err = ioutil.WriteFile(filepath.Join(moduleDir, marker), []byte{}, 0644)
+		assert.NoError(t, err)
+		_, ok = Find(packageDir)
+		assert.False(t, ok, marker)
+		os.Remove(filepath.Join(moduleDir, marker))
+	}
+
+	// a config in the root itself is found
+	err = ioutil.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte{}, 0644)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(moduleDir, Filename), []byte(`{}`), 0644)
+	assert.NoError(t, err)
+	filename, ok = Find(packageDir)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(moduleDir, Filename), filename)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, invalid := range []struct {
+		content string
+		message string
+	}{
+		{`{"plugins": [{"name": "docs"}]}`, "plugin 1 needs a name and a command"},
+		{`{"plugins": [{"name": "docs", "command": "a"}, {"name": "docs", "command": "b"}]}`, "plugin docs is declared twice"},
+		{`{"plugins": [{"name": "docs", "command": "a", "annotations": [{"name": "Doc", "paramTypes": {"n": "date"}}]}]}`, "parameter n of annotation @Doc of plugin docs has unknown type date"},
+		{`{"plugins": [{"name": "docs", "command": "a", "annotations": [{"name": "Doc", "targets": ["package"]}]}]}`, "annotation @Doc of plugin docs has unknown target package"},
+		{`{"plugins": `, "Error parsing config"},
+	} {
+		filename := filepath.Join(dir, Filename)
+		err = ioutil.WriteFile(filename, []byte(invalid.content), 0644)
+		assert.NoError(t, err)
+		_, err = Load(filename)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), invalid.message)
+	}
+}
@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Output receives the diagnostics that are reported while parsing and generating
+var Output io.Writer = os.Stderr
+
+// Diagnostic is an error or warning about an element in the sources.
+// It is rendered as file:line:col: message so that editors and CI can jump to the offending source.
+type Diagnostic struct {
+	Position model.Position
+	Severity Severity
+	Message  string
+}
+
+// Errorf returns an error that points to the given position
+func Errorf(position model.Position, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Position: position,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Warningf returns a warning that points to the given position
+func Warningf(position model.Position, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Position: position,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Warnf reports a warning that does not stop generation
+func Warnf(position model.Position, format string, args ...interface{}) {
+	Report(Warningf(position, format, args...))
+}
+
+// Report writes the diagnostic on a line of its own
+func Report(diagnostic Diagnostic) {
+	fmt.Fprintln(Output, diagnostic.String())
+}
+
+func (d Diagnostic) Error() string {
+	return d.String()
+}
+
+func (d Diagnostic) String() string {
+	message := d.Message
+	if d.Severity == SeverityWarning {
+		message = "warning: " + message
+	}
+	if d.Position.Filename == "" && !d.Position.IsValid() {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", d.Position, message)
+}
@@ -0,0 +1,41 @@
+package diagnostics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	err := Errorf(model.Position{Filename: "service/service.go", Line: 12, Column: 6}, "Rest-service %s is generic", "Service")
+	assert.Equal(t, "service/service.go:12:6: Rest-service Service is generic", err.Error())
+}
+
+func TestErrorfWithoutPosition(t *testing.T) {
+	err := Errorf(model.Position{}, "No position")
+	assert.Equal(t, "No position", err.Error())
+}
+
+func TestErrorfWrapped(t *testing.T) {
+	err := fmt.Errorf("Error generating module %s: %w", "rest", Errorf(model.Position{Filename: "a.go", Line: 3}, "Bad"))
+
+	var diagnostic Diagnostic
+	assert.True(t, errors.As(err, &diagnostic))
+	assert.Equal(t, "a.go:3: Bad", diagnostic.String())
+}
+
+func TestWarnf(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() {
+		Output = os.Stderr
+	}()
+
+	Warnf(model.Position{Filename: "a.go", Line: 3, Column: 1}, "Unused %s", "field")
+	assert.Equal(t, "a.go:3:1: warning: Unused field\n", buf.String())
+}
@@ -43,7 +43,7 @@ type TourService struct {
 }
 
 // @RestOperation( method = "GET", path = "/{year}", format = "JSON" )
-func (ts TourService) getTourOnUID(c context.Context, year int) (*Tour, error) {
+func (ts *TourService) getTourOnUID(c context.Context, year int) (*Tour, error) {
 	return &Tour{
 		Year:     2016,
 		Cyclists: []Cyclist{},
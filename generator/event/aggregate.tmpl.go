@@ -4,10 +4,12 @@ const aggregateTemplate = `// Generated automatically by golangAnnotations: do n
 
 package {{.PackageName}}
 
-import (
+{{define "logger"}}mylog.New(){{end -}}
+
+{{block "imports" .}}import (
 	"fmt"
 	"golang.org/x/net/context"
-)
+){{end}}
 
 const (
 {{range $aggr, $events := .AggregateMap -}}
@@ -43,7 +45,7 @@ type {{$aggr}}Aggregate interface {
 // Apply{{$aggr}}Event applies a single event to aggregate {{$aggr}}
 func Apply{{$aggr}}Event(c context.Context, envlp envelope.Envelope, aggregateRoot {{$aggr}}Aggregate) error {
 	if aggregateRoot.IsEventProcessed(envlp.UUID){
-		 mylog.New().Warning(c, "Event %+v already processed", envlp)
+		 {{template "logger"}}.Warning(c, "Event %+v already processed", envlp)
 		 return nil
 	}
 
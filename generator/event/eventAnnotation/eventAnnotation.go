@@ -13,9 +13,22 @@ const (
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
-			Name:       TypeEvent,
-			ParamNames: []string{ParamAggregate, ParamIsRootEvent, ParamIsTransient},
-			Validator:  validateEventAnnotation,
+			Name:        TypeEvent,
+			Description: "Generates an envelope, a publisher and aggregate-helpers for the event-struct",
+			ParamNames:  []string{ParamAggregate, ParamIsRootEvent, ParamIsTransient},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamIsRootEvent: annotation.ParamTypeBool,
+				ParamIsTransient: annotation.ParamTypeBool,
+			},
+			ParamDescriptions: map[string]string{
+				ParamAggregate:   "Aggregate the event belongs to",
+				ParamIsRootEvent: "The event creates the aggregate",
+				ParamIsTransient: "The event is published but not stored",
+			},
+			Required:  []string{ParamAggregate},
+			Unique:    true,
+			Targets:   []annotation.Target{annotation.TargetStruct},
+			Validator: validateEventAnnotation,
 		},
 	}
 }
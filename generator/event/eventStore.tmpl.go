@@ -4,7 +4,9 @@ const eventStoreTemplate = `// Generated automatically by golangAnnotations: do
 
 package {{.PackageName}}Store
 
-import "golang.org/x/net/context"
+{{define "timestamp-location"}}mytime.DutchLocation{{end -}}
+
+{{block "imports" .}}import "golang.org/x/net/context"{{end}}
 
 {{range .Structs -}}
 
@@ -32,7 +34,7 @@ func StoreEvent{{.Name}}(c context.Context, rc request.Context, evt *{{.PackageN
 
     evt.Metadata = {{.PackageName}}.Metadata{
         UUID:          envlp.UUID,
-        Timestamp:     envlp.Timestamp.In(mytime.DutchLocation),
+        Timestamp:     envlp.Timestamp.In({{template "timestamp-location"}}),
         EventTypeName: envlp.EventTypeName,
     }
 
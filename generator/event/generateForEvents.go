@@ -2,11 +2,11 @@ package event
 
 import (
 	"fmt"
-	"log"
 	"text/template"
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/event/eventAnnotation"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
@@ -36,6 +36,8 @@ type structures struct {
 type Generator struct {
 }
 
+var annotationRegistry = annotation.NewRegistry(eventAnnotation.Get())
+
 func NewGenerator() generationUtil.Generator {
 	return &Generator{}
 }
@@ -49,6 +51,10 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 }
 
 func generate(inputDir string, structs []model.Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
+
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if err != nil {
 		return err
@@ -130,8 +136,7 @@ func generateAggregates(targetDir, packageName string, structs []model.Struct) e
 	target := filegen.Prefixed(fmt.Sprintf("%s/aggregates.go", targetDir))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "aggregates", aggregateTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating aggregates (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, IsEvent), "Error generating aggregates: %s", err)
 	}
 	return nil
 }
@@ -149,8 +154,7 @@ func generateWrappers(targetDir, packageName string, structs []model.Struct) err
 	target := filegen.Prefixed(fmt.Sprintf("%s/wrappers.go", targetDir))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "wrappers", wrappersTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating wrappers for structures (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, IsEvent), "Error generating wrappers for structures: %s", err)
 	}
 	return nil
 }
@@ -164,6 +168,16 @@ func containsAny(structs []model.Struct, predicate func(_ model.Struct) bool) bo
 	return false
 }
 
+// firstPosition is where to report a problem with a generated file: at the first struct it is based on
+func firstPosition(structs []model.Struct, predicate func(_ model.Struct) bool) model.Position {
+	for _, s := range structs {
+		if predicate(s) {
+			return s.Position
+		}
+	}
+	return model.Position{}
+}
+
 func generateEventStore(targetDir, packageName string, structs []model.Struct) error {
 
 	if !containsAny(structs, IsPersistentEvent) {
@@ -177,8 +191,7 @@ func generateEventStore(targetDir, packageName string, structs []model.Struct) e
 	target := filegen.Prefixed(fmt.Sprintf("%s/../store/%sStore/%sStore.go", targetDir, packageName, packageName))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "event-store", eventStoreTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating event-store for structures (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, IsPersistentEvent), "Error generating event-store for structures: %s", err)
 	}
 	return nil
 }
@@ -196,8 +209,7 @@ func generateEventPublisher(targetDir, packageName string, structs []model.Struc
 	target := filegen.Prefixed(fmt.Sprintf("%s/../publisher/%sPublisher/%sPublisher.go", targetDir, packageName, packageName))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "event-publisher", eventPublisherTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating event-publisher for structures (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, isTransient), "Error generating event-publisher for structures: %s", err)
 	}
 	return nil
 }
@@ -215,8 +227,7 @@ func generateWrappersTest(targetDir, packageName string, structs []model.Struct)
 	target := filegen.Prefixed(fmt.Sprintf("%s/wrappers_test.go", targetDir))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "wrappers-test", wrappersTestTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating wrappers-test for structures (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, IsEvent), "Error generating wrappers-test for structures: %s", err)
 	}
 	return nil
 }
@@ -234,12 +245,16 @@ func generateHandlerInterface(targetDir, packageName string, structs []model.Str
 	target := filegen.Prefixed(fmt.Sprintf("%s/interface.go", targetDir))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "interface", interfaceTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating interface for event-handlers (%s)", err)
-		return err
+		return diagnostics.Errorf(firstPosition(structs, IsEvent), "Error generating interface for event-handlers: %s", err)
 	}
 	return nil
 }
 
+// TemplateFuncs returns the functions available in the templates of the generator
+func TemplateFuncs() template.FuncMap {
+	return customTemplateFuncs
+}
+
 var customTemplateFuncs = template.FuncMap{
 	"GetEvents":                 GetEvents,
 	"IsEvent":                   IsEvent,
@@ -263,14 +278,12 @@ func GetEvents(thecontext structures) []model.Struct {
 }
 
 func IsEvent(s model.Struct) bool {
-	annotations := annotation.NewRegistry(eventAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent)
+	_, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventAnnotation.TypeEvent)
 	return ok
 }
 
 func GetAggregateName(s model.Struct) string {
-	annotations := annotation.NewRegistry(eventAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventAnnotation.TypeEvent); ok {
 		return ann.Attributes[eventAnnotation.ParamAggregate]
 	}
 	return ""
@@ -280,9 +293,8 @@ func GetAggregateNameLowerCase(s model.Struct) string {
 	return toFirstLower(GetAggregateName(s))
 }
 func IsRootEvent(s model.Struct) bool {
-	annotations := annotation.NewRegistry(eventAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
-		return ann.Attributes[eventAnnotation.ParamIsRootEvent] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventAnnotation.TypeEvent); ok {
+		return ann.GetBool(eventAnnotation.ParamIsRootEvent)
 	}
 	return false
 }
@@ -296,9 +308,8 @@ func IsTransientEvent(s model.Struct) bool {
 }
 
 func isTransient(s model.Struct) bool {
-	annotations := annotation.NewRegistry(eventAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventAnnotation.TypeEvent); ok {
-		return ann.Attributes[eventAnnotation.ParamIsTransient] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventAnnotation.TypeEvent); ok {
+		return ann.GetBool(eventAnnotation.ParamIsTransient)
 	}
 	return false
 }
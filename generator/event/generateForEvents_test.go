@@ -1,11 +1,15 @@
 package event
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -86,3 +90,29 @@ func TestGetAggregateName(t *testing.T) {
 	}
 	assert.Equal(t, "person", GetAggregateName(s))
 }
+
+func TestGenerateForEventsReportsError(t *testing.T) {
+	cleanup()
+	defer cleanup()
+	templateDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(templateDir)
+	err = ioutil.WriteFile(filepath.Join(templateDir, "aggregates.tmpl"), []byte(`{{.Unknown}}`), 0644)
+	assert.NoError(t, err)
+	generationUtil.SetTemplateDir(templateDir)
+	defer generationUtil.SetTemplateDir("")
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Position:    model.Position{Filename: "testData/events.go", Line: 3, Column: 6},
+			DocLines:    []string{`//@Event(aggregate = "Test")`},
+			Name:        "MyStruct",
+		},
+	}
+	err = NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	var diagnostic diagnostics.Diagnostic
+	assert.True(t, errors.As(err, &diagnostic))
+	assert.Equal(t, s[0].Position, diagnostic.Position)
+	assert.Contains(t, diagnostic.Message, "Error generating aggregates")
+}
@@ -4,11 +4,11 @@ const interfaceTemplate = `// Generated automatically by golangAnnotations: do n
 
 package {{.PackageName}}
 
-import (
+{{block "imports" .}}import (
 	"golang.org/x/net/context"
 
     "github.com/Duxxie/platform/backend/lib/request"
-)
+){{end}}
 
 {{$packageName := .PackageName}}
 
@@ -4,11 +4,14 @@ const wrappersTemplate = `// Generated automatically by golangAnnotations: do no
 
 package {{.PackageName}}
 
-import (
+{{define "now"}}mytime.Now(){{end -}}
+{{define "timestamp-location"}}mytime.DutchLocation{{end -}}
+
+{{block "imports" .}}import (
     "encoding/json"
     "fmt"
     "log"
-)
+){{end}}
 
 const (
 {{range .Structs -}}
@@ -33,7 +36,7 @@ func (s *{{.Name}}) Wrap(rc request.Context) (*envelope.Envelope,error) {
         IsRootEvent:{{if IsRootEvent .}}true{{else}}false{{end}},
         SequenceNumber: int64(0), // Set later by event-store
         SessionUID: rc.GetSessionUID(),
-        Timestamp: mytime.Now(),
+        Timestamp: {{template "now"}},
         AggregateName: {{GetAggregateName . }}AggregateName, // from annotation!
         AggregateUID:  s.GetUID(),
         EventTypeName: {{.Name}}EventName,
@@ -82,7 +85,7 @@ func UnWrap{{.Name}}(envlp *envelope.Envelope) (*{{.Name}},error) {
     evt.Metadata = Metadata{
         UUID:          envlp.UUID,
 		AdminUserUID:  envlp.AdminUserUID,
-        Timestamp:     envlp.Timestamp.In(mytime.DutchLocation),
+        Timestamp:     envlp.Timestamp.In({{template "timestamp-location"}}),
         EventTypeName: envlp.EventTypeName,
     }
 
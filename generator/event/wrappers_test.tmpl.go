@@ -6,12 +6,12 @@ const wrappersTestTemplate = `// +build !appengine
 
 package {{.PackageName}}
 
-import (
+{{block "imports" .}}import (
     "reflect"
     "testing"
     "time"
     "github.com/stretchr/testify/assert"
-)
+){{end}}
 
 {{range .Structs -}}
     {{if IsEvent . -}}
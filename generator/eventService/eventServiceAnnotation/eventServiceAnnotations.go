@@ -16,14 +16,38 @@ const (
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
-			Name:       TypeEventService,
-			ParamNames: []string{ParamSelf, ParamNoTest},
-			Validator:  validateEventServiceAnnotation,
+			Name:        TypeEventService,
+			Description: "Generates http-handlers that dispatch received events to the event-operations of the struct",
+			ParamNames:  []string{ParamSelf, ParamNoTest},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamNoTest: annotation.ParamTypeBool,
+			},
+			ParamDescriptions: map[string]string{
+				ParamSelf:   "Name by which the service subscribes",
+				ParamNoTest: "Do not generate test-helpers",
+			},
+			Unique:    true,
+			Targets:   []annotation.Target{annotation.TargetStruct},
+			Validator: validateEventServiceAnnotation,
 		},
 		{
-			Name:       TypeEventOperation,
-			ParamNames: []string{ParamTopic, ParamProcess, ParamDelay},
-			Validator:  validateEventOperationAnnotation,
+			Name:        TypeEventOperation,
+			Description: "Subscribes the method to the events of a topic",
+			ParamNames:  []string{ParamTopic, ParamProcess, ParamDelay, ParamProducesEvents},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamProducesEvents: annotation.ParamTypeList,
+			},
+			ParamDescriptions: map[string]string{
+				ParamTopic:          "Topic to subscribe to",
+				ParamProcess:        "Process that handles the events: operations of the same process share a queue",
+				ParamDelay:          "Delay before the event is handled, as duration like 30s",
+				ParamProducesEvents: "Events produced by the operation, like Aggregate.Event",
+			},
+			Required:          []string{ParamTopic},
+			Unique:            true,
+			RequiresEnclosing: []string{TypeEventService},
+			Targets:           []annotation.Target{annotation.TargetOperation},
+			Validator:         validateEventOperationAnnotation,
 		}}
 }
 
@@ -2,7 +2,6 @@ package eventService
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 	"text/template"
@@ -10,6 +9,7 @@ import (
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/eventService/eventServiceAnnotation"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
@@ -19,6 +19,8 @@ import (
 type Generator struct {
 }
 
+var annotationRegistry = annotation.NewRegistry(eventServiceAnnotation.Get())
+
 func NewGenerator() generationUtil.Generator {
 	return &Generator{}
 }
@@ -37,6 +39,9 @@ type templateData struct {
 }
 
 func generate(inputDir string, structs []model.Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if err != nil {
@@ -50,6 +55,10 @@ func generate(inputDir string, structs []model.Struct) error {
 	eventServices := []model.Struct{}
 	for _, service := range structs {
 		if IsEventService(service) {
+			err = validateEventOperations(service)
+			if err != nil {
+				return err
+			}
 			eventServices = append(eventServices, service)
 		}
 	}
@@ -65,13 +74,24 @@ func generate(inputDir string, structs []model.Struct) error {
 	return doGenerate(targetDir, packageName, eventServices, data)
 }
 
+// validateEventOperations rejects value-receivers that would make the event-handlers operate on a copy of the service:
+// a service without fields has no state to copy
+func validateEventOperations(service model.Struct) error {
+	for _, o := range service.Operations {
+		if IsEventOperation(*o) && o.HasValueReceiver() && len(service.Fields) > 0 {
+			return diagnostics.Errorf(o.Position, "Event-operation %s.%s has a value-receiver: use a pointer-receiver so it does not operate on a copy of the service",
+				service.Name, o.Name)
+		}
+	}
+	return nil
+}
+
 func doGenerate(targetDir, packageName string, eventServices []model.Struct, data templateData) error {
 
 	target := filegen.Prefixed(fmt.Sprintf("%s/eventHandler.go", targetDir))
 	err := generationUtil.GenerateFileFromTemplate(data, packageName, "event-handlers", handlersTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating handlers for event-services in package %s: %s", packageName, err)
-		return err
+		return diagnostics.Errorf(eventServices[0].Position, "Error generating handlers for event-services in package %s: %s", packageName, err)
 	}
 
 	for _, eventService := range eventServices {
@@ -79,8 +99,7 @@ func doGenerate(targetDir, packageName string, eventServices []model.Struct, dat
 			target = filegen.Prefixed(fmt.Sprintf("%s/eventHandlerHelpers_test.go", targetDir))
 			err = generationUtil.GenerateFileFromTemplate(data, packageName, "test-handlers", testHandlersTemplate, customTemplateFuncs, target)
 			if err != nil {
-				log.Fatalf("Error generating test-handlers for event-services in package %s: %s", packageName, err)
-				return err
+				return diagnostics.Errorf(eventService.Position, "Error generating test-handlers for event-services in package %s: %s", packageName, err)
 			}
 			break
 		}
@@ -89,8 +108,62 @@ func doGenerate(targetDir, packageName string, eventServices []model.Struct, dat
 	return nil
 }
 
+func isImportToBeIgnored(imp string) bool {
+	if imp == "" {
+		return true
+	}
+	// already imported by the template
+	for _, i := range []string{
+		"encoding/json",
+		"fmt",
+		"net/http",
+		"context",
+		"golang.org/x/net/context",
+		"github.com/gorilla/mux",
+	} {
+		if imp == i {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractImports returns the import-specs for the packages of the input-args of the constructors,
+// that the handlers accept to create the event-services
+func ExtractImports(data templateData) []string {
+	importsMap := map[string]string{}
+	for _, service := range data.Services {
+		constructor := service.Constructor()
+		if constructor == nil {
+			continue
+		}
+		for _, arg := range constructor.InputArgs {
+			for packagePath, spec := range generationUtil.GetImportSpecs(arg) {
+				if isImportToBeIgnored(packagePath) == false {
+					importsMap[packagePath] = spec
+				}
+			}
+		}
+	}
+	importsList := []string{}
+	for _, v := range importsMap {
+		importsList = append(importsList, v)
+	}
+	sort.Strings(importsList)
+
+	return importsList
+}
+
+// TemplateFuncs returns the functions available in the templates of the generator
+func TemplateFuncs() template.FuncMap {
+	return customTemplateFuncs
+}
+
 var customTemplateFuncs = template.FuncMap{
 	"IsEventService":                  IsEventService,
+	"GetConstructorParams":            generationUtil.GetConstructorParams,
+	"GetConstructorArgs":              generationUtil.GetConstructorArgs,
+	"ExtractImports":                  ExtractImports,
 	"IsEventServiceNoTest":            IsEventServiceNoTest,
 	"IsEventOperation":                IsEventOperation,
 	"GetInputArgType":                 GetInputArgType,
@@ -109,8 +182,7 @@ var customTemplateFuncs = template.FuncMap{
 }
 
 func IsEventService(s model.Struct) bool {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService)
+	_, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventServiceAnnotation.TypeEventService)
 	return ok
 }
 
@@ -125,32 +197,22 @@ func IsEventNotTransient(o model.Operation) bool {
 }
 
 func IsEventServiceNoTest(s model.Struct) bool {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
-		return ann.Attributes[eventServiceAnnotation.ParamNoTest] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+		return ann.GetBool(eventServiceAnnotation.ParamNoTest)
 	}
 	return false
 }
 
 func GetEventServiceSelfName(s model.Struct) string {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, eventServiceAnnotation.TypeEventService); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, eventServiceAnnotation.TypeEventService); ok {
 		return ann.Attributes[eventServiceAnnotation.ParamSelf]
 	}
 	return ""
 }
 
 func GetEventOperationProducesEventsAsSlice(o model.Operation) []string {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
-		if attrs, ok := ann.Attributes[eventServiceAnnotation.ParamProducesEvents]; ok {
-			eventsProduced := []string{}
-			for _, e := range strings.Split(attrs, ",") {
-				evt := strings.TrimSpace(e)
-				if evt != "" {
-					eventsProduced = append(eventsProduced, evt)
-				}
-			}
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+		if eventsProduced, ok := ann.GetStrings(eventServiceAnnotation.ParamProducesEvents); ok {
 			return eventsProduced
 		}
 	}
@@ -203,14 +265,12 @@ func GetFullEventNames(s model.Struct) []string {
 }
 
 func IsEventOperation(o model.Operation) bool {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation)
+	_, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, eventServiceAnnotation.TypeEventOperation)
 	return ok
 }
 
 func GetEventOperationTopic(o model.Operation) string {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
 		return ann.Attributes[eventServiceAnnotation.ParamTopic]
 	}
 	return ""
@@ -245,8 +305,7 @@ operations:
 }
 
 func GetEventOperationProcess(o model.Operation) string {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
 		process := ann.Attributes[eventServiceAnnotation.ParamProcess]
 		if process != "" {
 			return ToFirstUpper(process)
@@ -269,8 +328,7 @@ func IsAnyEventOperationDelayed(s model.Struct) bool {
 }
 
 func GetEventOperationDelay(o model.Operation) float64 {
-	annotations := annotation.NewRegistry(eventServiceAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, eventServiceAnnotation.TypeEventOperation); ok {
 		delay := ann.Attributes[eventServiceAnnotation.ParamDelay]
 		duration, err := time.ParseDuration(delay)
 		if err == nil {
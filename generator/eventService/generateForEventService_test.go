@@ -58,6 +58,72 @@ func TestGenerateForWeb(t *testing.T) {
 	assert.Contains(t, string(data), `func (es *MyEventService) handleEvent(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error{`)
 }
 
+func TestGenerateWithConstructor(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @EventService( self = "self" )`},
+			PackageName: "testData",
+			Name:        "MyEventService",
+			Constructors: []*model.Operation{
+				{
+					Name:       "NewMyEventService",
+					InputArgs:  []model.Field{{Name: "store", TypeName: "Store"}, {Name: "db", TypeName: "sql.DB", IsPointer: true, PackageName: "database/sql"}},
+					OutputArgs: []model.Field{{TypeName: "MyEventService"}, {TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(filegen.Prefixed("./testData/eventHandler.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"database/sql"`)
+	assert.Contains(t, string(data), `func NewMyEventServiceSubscribed(router *mux.Router, store Store, db *sql.DB) (*MyEventService, error) {`)
+	assert.Contains(t, string(data), `service, err := NewMyEventService(store, db)`)
+	assert.Contains(t, string(data), `es := &service`)
+	assert.Contains(t, string(data), `return es, nil`)
+}
+
+func TestGenerateForValueReceiver(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{`// @EventService( self = "self" )`},
+			PackageName: "testData",
+			Name:        "MyEventService",
+			Fields:      []model.Field{{Name: "store", TypeName: "Store"}},
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{`// @EventOperation( topic = "other" )`},
+					Position:      model.Position{Filename: "testData/service.go", Line: 7, Column: 25},
+					Name:          "doit",
+					RelatedStruct: &model.Field{Name: "es", TypeName: "MyEventService"},
+					ReceiverKind:  model.ReceiverValue,
+					InputArgs: []model.Field{
+						{Name: "c", TypeName: "context.Context"},
+						{Name: "structExample", TypeName: "events.OrderCreated"},
+					},
+					OutputArgs: []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	assert.Error(t, err)
+	assert.Equal(t, "testData/service.go:7:25: Event-operation MyEventService.doit has a value-receiver: use a pointer-receiver so it does not operate on a copy of the service", err.Error())
+
+	_, err = os.Stat(filegen.Prefixed("./testData/eventHandler.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestIsRestService(t *testing.T) {
 	s := model.Struct{
 		DocLines: []string{
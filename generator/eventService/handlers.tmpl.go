@@ -4,13 +4,17 @@ const handlersTemplate = `// Generated automatically by golangAnnotations: do no
 
 package {{.PackageName}}
 
-import (
+{{define "logger"}}mylog.New(){{end -}}
+{{define "create-context"}}ctx.New.CreateContext(r){{end -}}
+
+{{block "imports" .}}import (
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"golang.org/x/net/context"
-	"github.com/gorilla/mux"
-)
+	"github.com/gorilla/mux"{{range ExtractImports .}}
+	{{.}}{{end}}
+){{end}}
 
 {{range $idxService, $service := .Services -}}
 
@@ -27,6 +31,23 @@ func (es *{{$eventServiceName}}) SubscribeToEvents(router *mux.Router) {
 	{{end -}}
 }
 
+{{with .Constructor -}}
+// New{{ToFirstUpper $eventServiceName}}Subscribed creates the event-service with {{.Name}} and subscribes it to its topics
+func New{{ToFirstUpper $eventServiceName}}Subscribed(router *mux.Router{{if .InputArgs}}, {{GetConstructorParams .}}{{end}}) {{if .ReturnsError}}(*{{$eventServiceName}}, error){{else}}*{{$eventServiceName}}{{end}} {
+	{{if .ReturnsError -}}
+	service, err := {{.Name}}({{GetConstructorArgs .}})
+	if err != nil {
+		return nil, err
+	}
+	{{- else -}}
+	service := {{.Name}}({{GetConstructorArgs .}})
+	{{- end}}
+	es := {{if not (index .OutputArgs 0).IsPointer}}&{{end}}service
+	es.SubscribeToEvents(router)
+	return es{{if .ReturnsError}}, nil{{end}}
+}
+{{end -}}
+
 func (es *{{$eventServiceName}}) enqueueEventToBackground(c context.Context, rc request.Context, topic string, envlp envelope.Envelope) error{
 	const subscriber = "{{GetEventServiceSelfName .}}"
 	switch envlp.EventTypeName {
@@ -63,7 +84,7 @@ func (es *{{$eventServiceName}}) enqueueEventToBackground(c context.Context, rc
 				return err
 			}
 
-			mylog.New().Debug(c, "Subscriber '%s' enqueued task on topic '%s' with event '%s'", subscriber, topic, envlp.NiceName())
+			{{template "logger"}}.Debug(c, "Subscriber '%s' enqueued task on topic '%s' with event '%s'", subscriber, topic, envlp.NiceName())
 
 			return nil
 	}
@@ -84,15 +105,15 @@ func (es *{{$eventServiceName}}) getProcessTypeFor(envlp envelope.Envelope) myqu
 
 func (es *{{$eventServiceName}}) handleHttpBackgroundEvent() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		c := ctx.New.CreateContext(r)
+		c := {{template "create-context"}}
 
 		retryCount, err := strconv.Atoi(r.Header.Get("X-AppEngine-TaskRetryCount"))
 		if err != nil {
-			mylog.New().Warning(c, "Error parsing 'X-AppEngine-TaskRetryCount': %s", err)
+			{{template "logger"}}.Warning(c, "Error parsing 'X-AppEngine-TaskRetryCount': %s", err)
 		}
 
 		if retryCount > 0 && !environ.GetEnvironment(c).RetryFailedEvents(c) {
-			mylog.New().Info(c, "Abort retry scheme after %d rertries because of env-setting", retryCount)
+			{{template "logger"}}.Info(c, "Abort retry scheme after %d rertries because of env-setting", retryCount)
 			return
 		}
 
@@ -6,10 +6,10 @@ const testHandlersTemplate = `// +build !appengine
 
 package {{.PackageName}}
 
-import (
+{{block "imports" .}}import (
     "golang.org/x/net/context"
     "github.com/gorilla/mux"
-)
+){{end}}
 
 {{range $idxService, $service := .Services -}}
 
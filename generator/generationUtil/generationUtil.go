@@ -1,11 +1,15 @@
 package generationUtil
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
@@ -96,24 +100,222 @@ func DetermineTargetPath(inputDir string, packageName string) (string, error) {
 	return fmt.Sprintf("%s/%s", inputDir, packageName), nil
 }
 
-func GenerateFileFromTemplate(data interface{}, srcName string, templateName string, templateString string, funcMap template.FuncMap, targetFileName string) error {
-	fmt.Fprintf(os.Stderr, "%s: Generated go file '%s' based on source '%s'\n", "golangAnnotations", targetFileName, srcName)
+var (
+	templatesMutex sync.Mutex
+	templateDir    string
+	templateFuncs  = template.FuncMap{}
+)
+
+// SetTemplateDir makes GenerateFileFromTemplate look for overrides of its templates in dir: empty for none
+func SetTemplateDir(dir string) {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+
+	templateDir = dir
+}
+
+// RegisterTemplateFuncs makes the functions available in the templates of all generators, so that overrides
+// can use the helpers of other generators. The functions of the generator itself take precedence, and
+// a function registered earlier takes precedence over one with the same name registered later.
+func RegisterTemplateFuncs(funcMap template.FuncMap) {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+
+	for name, fn := range funcMap {
+		if _, exists := templateFuncs[name]; !exists {
+			templateFuncs[name] = fn
+		}
+	}
+}
 
-	err := os.MkdirAll(filepath.Dir(targetFileName), 0777)
+func GenerateFileFromTemplate(data interface{}, srcName string, templateName string, templateString string, funcMap template.FuncMap, targetFileName string) error {
+	t, err := parseTemplate(templateName, templateString, funcMap)
 	if err != nil {
 		return err
 	}
-	w, err := os.Create(targetFileName)
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
 	if err != nil {
 		return err
 	}
 
-	t := template.New(templateName).Funcs(funcMap)
-	t, err = t.Parse(templateString)
+	return WriteGeneratedFile(buf.Bytes(), srcName, targetFileName)
+}
+
+// parseTemplate parses the built-in template and its override in the template-dir, named after the template:
+// like http-handlers.tmpl. An override with a body replaces the whole template, an override that only
+// contains {{define}}-blocks replaces these blocks.
+func parseTemplate(templateName string, templateString string, funcMap template.FuncMap) (*template.Template, error) {
+	templatesMutex.Lock()
+	t := template.New(templateName).Funcs(templateFuncs)
+	dir := templateDir
+	templatesMutex.Unlock()
+
+	t, err := t.Funcs(funcMap).Parse(templateString)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return t, nil
+	}
+	overrideFilename := filepath.Join(dir, templateName+".tmpl")
+	override, err := ioutil.ReadFile(overrideFilename)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err = t.Parse(string(override))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing template-override %s:%s", overrideFilename, err)
+	}
+	return t, nil
+}
+
+// WriteGeneratedFile writes generated content to the target file, creating its directory when needed
+func WriteGeneratedFile(content []byte, srcName string, targetFileName string) error {
+	// Leave identical files untouched: a new modification-time would trigger needless rebuilds
+	existing, err := ioutil.ReadFile(targetFileName)
+	if err == nil && bytes.Equal(existing, content) {
+		fmt.Fprintf(os.Stderr, "%s: Unchanged go file '%s' based on source '%s'\n", "golangAnnotations", targetFileName, srcName)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: Generated go file '%s' based on source '%s'\n", "golangAnnotations", targetFileName, srcName)
+
+	err = os.MkdirAll(filepath.Dir(targetFileName), 0777)
 	if err != nil {
 		return err
 	}
-	defer w.Close()
+	return ioutil.WriteFile(targetFileName, content, 0644)
+}
+
+// GetJSONName returns the name of the field in json, honouring a rename in its json-tag: empty when the field is ignored
+func GetJSONName(f model.Field) string {
+	if IsJSONIgnored(f) {
+		return ""
+	}
+	tag, ok := f.LookupTag("json")
+	if ok && tag.Name != "" {
+		return tag.Name
+	}
+	return f.Name
+}
+
+// IsJSONIgnored recognizes fields tagged with `json:"-"`
+func IsJSONIgnored(f model.Field) bool {
+	tag, ok := f.LookupTag("json")
+	return ok && tag.Name == "-" && len(tag.Options) == 0
+}
 
-	return t.Execute(w, data)
+func IsJSONOmitEmpty(f model.Field) bool {
+	tag, ok := f.LookupTag("json")
+	return ok && tag.HasOption("omitempty")
+}
+
+// GetConstructorParams declares the input-args of a constructor, so that wiring-code can accept and pass them on
+func GetConstructorParams(o model.Operation) string {
+	params := []string{}
+	for idx, arg := range o.InputArgs {
+		params = append(params, fmt.Sprintf("%s %s", getConstructorArgName(idx, arg), arg.TypeString()))
+	}
+	return strings.Join(params, ", ")
+}
+
+// GetConstructorArgs passes the params declared by GetConstructorParams on to the constructor
+func GetConstructorArgs(o model.Operation) string {
+	args := []string{}
+	for idx, arg := range o.InputArgs {
+		name := getConstructorArgName(idx, arg)
+		if arg.IsVariadic {
+			name += "..."
+		}
+		args = append(args, name)
+	}
+	return strings.Join(args, ", ")
+}
+
+func getConstructorArgName(idx int, arg model.Field) string {
+	if arg.Name == "" || arg.Name == "_" {
+		return fmt.Sprintf("arg%d", idx)
+	}
+	return arg.Name
+}
+
+// GetImportSpecs returns the import-specs by import-path for the packages that the arg refers to, also within
+// map, func and chan types: aliased when the arg refers to a package by another name than the package-clause
+func GetImportSpecs(arg model.Field) map[string]string {
+	specs := map[string]string{}
+	if arg.PackageName != "" {
+		specs[arg.PackageName] = getImportSpec(getQualifier(arg), arg.PackageName)
+	}
+	collectImportSpecs(arg.Type, specs)
+	return specs
+}
+
+// getQualifier returns how the arg refers to its package: "." for a dot-import
+func getQualifier(arg model.Field) string {
+	if arg.Type == nil {
+		// hand-made models may lack the type: example: []*uuid.UUID
+		typeName := strings.TrimLeft(arg.TypeName, "[]*.")
+		if idx := strings.Index(typeName, "."); idx >= 0 {
+			return typeName[:idx]
+		}
+		return "."
+	}
+	if named := findNamedType(arg.Type, arg.PackageName); named != nil && named.Package != "" {
+		return named.Package
+	}
+	// a type from a dot-import has no qualifier: example: UUID instead of uuid.UUID
+	return "."
+}
+
+func findNamedType(mType *model.Type, packagePath string) *model.Type {
+	var found *model.Type
+	walkType(mType, func(t *model.Type) {
+		if found == nil && t.Kind == model.KindNamed && t.PackagePath == packagePath {
+			found = t
+		}
+	})
+	return found
+}
+
+func collectImportSpecs(mType *model.Type, specs map[string]string) {
+	walkType(mType, func(t *model.Type) {
+		// types of the own package have no qualifier, but are given a package-path when type-checked
+		if t.Kind == model.KindNamed && t.Package != "" && t.PackagePath != "" && specs[t.PackagePath] == "" {
+			specs[t.PackagePath] = getImportSpec(t.Package, t.PackagePath)
+		}
+	})
+}
+
+func walkType(mType *model.Type, visit func(t *model.Type)) {
+	if mType == nil {
+		return
+	}
+	visit(mType)
+	for idx := range mType.TypeArgs {
+		walkType(&mType.TypeArgs[idx], visit)
+	}
+	walkType(mType.Elem, visit)
+	walkType(mType.Key, visit)
+	walkType(mType.Value, visit)
+	for _, fields := range [][]model.Field{mType.Params, mType.Results, mType.Fields} {
+		for _, f := range fields {
+			walkType(f.Type, visit)
+		}
+	}
+	for _, m := range mType.Methods {
+		for _, f := range append(append([]model.Field{}, m.InputArgs...), m.OutputArgs...) {
+			walkType(f.Type, visit)
+		}
+	}
+}
+
+func getImportSpec(qualifier string, packagePath string) string {
+	if qualifier == model.AssumedPackageName(packagePath) {
+		return strconv.Quote(packagePath)
+	}
+	return fmt.Sprintf("%s %s", qualifier, strconv.Quote(packagePath))
 }
@@ -1,10 +1,15 @@
 package generationUtil
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/stretchr/testify/assert"
@@ -81,3 +86,136 @@ func TestGenerateFileFromTemplate(t *testing.T) {
 	os.Remove("./test/doit.txt")
 	os.Remove("./test")
 }
+
+func TestGenerateFileFromTemplateUnchanged(t *testing.T) {
+	defer os.RemoveAll("./test")
+
+	generate := func(packageName string) {
+		err := GenerateFileFromTemplate(model.Struct{PackageName: packageName}, "testsrc", "testtemplate", "{{.PackageName}}", template.FuncMap{}, "test/doit.txt")
+		assert.Nil(t, err)
+	}
+	generate("testit")
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	err := os.Chtimes("test/doit.txt", past, past)
+	assert.NoError(t, err)
+
+	// identical content: file is left untouched
+	generate("testit")
+	fi, err := os.Stat("test/doit.txt")
+	assert.NoError(t, err)
+	assert.True(t, fi.ModTime().Equal(past))
+
+	// changed content: file is rewritten
+	generate("other")
+	data, err := ioutil.ReadFile("test/doit.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "other", string(data))
+}
+
+func TestGenerateFileFromTemplateOverride(t *testing.T) {
+	defer os.RemoveAll("./test")
+	overrideDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(overrideDir)
+	SetTemplateDir(overrideDir)
+	defer SetTemplateDir("")
+	RegisterTemplateFuncs(template.FuncMap{"Shout": strings.ToUpper})
+
+	generate := func() string {
+		err := GenerateFileFromTemplate(model.Struct{PackageName: "testit"}, "testsrc", "testtemplate",
+			`package {{.PackageName}} {{block "logger" .}}mylog.New(){{end}}`, template.FuncMap{}, "test/doit.txt")
+		assert.NoError(t, err)
+		data, err := ioutil.ReadFile("test/doit.txt")
+		assert.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "package testit mylog.New()", generate())
+
+	// only a block
+	err = ioutil.WriteFile(filepath.Join(overrideDir, "testtemplate.tmpl"), []byte(`{{define "logger"}}log.New({{Shout .PackageName}}){{end}}`), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, "package testit log.New(TESTIT)", generate())
+
+	// the whole template
+	err = ioutil.WriteFile(filepath.Join(overrideDir, "testtemplate.tmpl"), []byte(`package {{.PackageName}}_override`), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, "package testit_override", generate())
+
+	err = ioutil.WriteFile(filepath.Join(overrideDir, "testtemplate.tmpl"), []byte(`{{.PackageName`), 0644)
+	assert.NoError(t, err)
+	err = GenerateFileFromTemplate(model.Struct{}, "testsrc", "testtemplate", "", template.FuncMap{}, "test/doit.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Error parsing template-override")
+}
+
+func TestRegisterTemplateFuncsWhileParsing(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterTemplateFuncs(template.FuncMap{fmt.Sprintf("Concurrent%d", i): strings.ToUpper})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := parseTemplate("concurrent", `{{.}}`, template.FuncMap{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestJSONTags(t *testing.T) {
+	plain := model.Field{Name: "Plain"}
+	assert.Equal(t, "Plain", GetJSONName(plain))
+	assert.False(t, IsJSONIgnored(plain))
+	assert.False(t, IsJSONOmitEmpty(plain))
+
+	renamed := model.Field{Name: "Renamed", Tags: []model.Tag{{Key: "db", Name: "db_name"}, {Key: "json", Name: "renamed", Options: []string{"omitempty"}}}}
+	assert.Equal(t, "renamed", GetJSONName(renamed))
+	assert.True(t, IsJSONOmitEmpty(renamed))
+
+	optionsOnly := model.Field{Name: "OptionsOnly", Tags: []model.Tag{{Key: "json", Options: []string{"omitempty"}}}}
+	assert.Equal(t, "OptionsOnly", GetJSONName(optionsOnly))
+
+	ignored := model.Field{Name: "Ignored", Tags: []model.Tag{{Key: "json", Name: "-"}}}
+	assert.True(t, IsJSONIgnored(ignored))
+	assert.Equal(t, "", GetJSONName(ignored))
+
+	dash := model.Field{Name: "Dash", Tags: []model.Tag{{Key: "json", Name: "-", Options: []string{""}}}}
+	assert.False(t, IsJSONIgnored(dash))
+	assert.Equal(t, "-", GetJSONName(dash))
+}
+
+func TestConstructorParamsAndArgs(t *testing.T) {
+	o := model.Operation{
+		Name: "NewService",
+		InputArgs: []model.Field{
+			{Name: "store", TypeName: "Store", IsPointer: true},
+			{TypeName: "int"},
+			{Name: "options", TypeName: "string", IsVariadic: true},
+		},
+	}
+	assert.Equal(t, "store *Store, arg1 int, options ...string", GetConstructorParams(o))
+	assert.Equal(t, "store, arg1, options...", GetConstructorArgs(o))
+	assert.Equal(t, "", GetConstructorParams(model.Operation{Name: "NewService"}))
+}
+
+func TestGetImportSpecs(t *testing.T) {
+	renamed := model.Field{Name: "ids", TypeName: "map[string]gofrs.UUID",
+		Type: &model.Type{Kind: model.KindMap, Key: &model.Type{Kind: model.KindNamed, Name: "string"},
+			Value: &model.Type{Kind: model.KindNamed, Name: "UUID", Package: "gofrs", PackagePath: "github.com/gofrs/uuid/v5"}}}
+	assert.Equal(t, map[string]string{"github.com/gofrs/uuid/v5": `gofrs "github.com/gofrs/uuid/v5"`}, GetImportSpecs(renamed))
+
+	callback := model.Field{Name: "cb", TypeName: "func(*sql.DB) money.Amount", PackageName: "database/sql",
+		Type: &model.Type{Kind: model.KindFunc,
+			Params:  []model.Field{{Type: &model.Type{Kind: model.KindPointer, Elem: &model.Type{Kind: model.KindNamed, Name: "DB", Package: "sql", PackagePath: "database/sql"}}}},
+			Results: []model.Field{{Type: &model.Type{Kind: model.KindNamed, Name: "Amount", PackagePath: "github.com/shop/money"}}}}}
+	// the own package has no qualifier
+	assert.Equal(t, map[string]string{"database/sql": `"database/sql"`}, GetImportSpecs(callback))
+
+	dotImported := model.Field{Name: "amount", TypeName: "Amount", PackageName: "github.com/shop/money",
+		Type: &model.Type{Kind: model.KindNamed, Name: "Amount", PackagePath: "github.com/shop/money"}}
+	assert.Equal(t, map[string]string{"github.com/shop/money": `. "github.com/shop/money"`}, GetImportSpecs(dotImported))
+}
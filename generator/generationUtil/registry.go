@@ -0,0 +1,61 @@
+package generationUtil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+)
+
+var (
+	generatorsMutex sync.Mutex
+	generators      = map[string]Generator{}
+)
+
+// RegisterGenerator makes the generator run for every package that is processed, under the given name:
+// the parser attaches the annotations of the generator to the model. Registering an equal generator
+// under the same name again has no effect, so that a process can configure the generators more than once.
+func RegisterGenerator(name string, generator Generator) error {
+	generatorsMutex.Lock()
+	defer generatorsMutex.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("Generator needs a name")
+	}
+	if generator == nil {
+		return fmt.Errorf("Generator %s is nil", name)
+	}
+	if existing, exists := generators[name]; exists {
+		if reflect.DeepEqual(existing, generator) {
+			return nil
+		}
+		return fmt.Errorf("Generator %s is already registered", name)
+	}
+	generators[name] = generator
+	annotation.RegisterDescriptors(name, generator.GetAnnotations())
+	return nil
+}
+
+// GetGenerator returns the generator registered under the given name
+func GetGenerator(name string) (Generator, bool) {
+	generatorsMutex.Lock()
+	defer generatorsMutex.Unlock()
+
+	generator, ok := generators[name]
+	return generator, ok
+}
+
+// GetGeneratorNames returns the names of the registered generators, sorted so that generators run in a fixed order
+func GetGeneratorNames() []string {
+	generatorsMutex.Lock()
+	defer generatorsMutex.Unlock()
+
+	names := []string{}
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
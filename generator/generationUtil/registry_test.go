@@ -0,0 +1,45 @@
+package generationUtil
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type nopGenerator struct{}
+
+func (nopGenerator) GetAnnotations() []annotation.AnnotationDescriptor {
+	return nil
+}
+
+func (nopGenerator) Generate(inputDir string, parsedSources model.ParsedSources) error {
+	return nil
+}
+
+type configuredGenerator struct {
+	nopGenerator
+	command string
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	assert.NoError(t, RegisterGenerator("test-b", nopGenerator{}))
+	assert.NoError(t, RegisterGenerator("test-a", nopGenerator{}))
+
+	g, ok := GetGenerator("test-a")
+	assert.True(t, ok)
+	assert.Equal(t, nopGenerator{}, g)
+	_, ok = GetGenerator("test-c")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"test-a", "test-b"}, GetGeneratorNames())
+
+	// an equal generator can be registered again, another one cannot
+	assert.NoError(t, RegisterGenerator("test-a", nopGenerator{}))
+	assert.NoError(t, RegisterGenerator("test-d", &configuredGenerator{command: "a"}))
+	assert.NoError(t, RegisterGenerator("test-d", &configuredGenerator{command: "a"}))
+	assert.Equal(t, "Generator test-d is already registered", RegisterGenerator("test-d", &configuredGenerator{command: "b"}).Error())
+	assert.Equal(t, "Generator test-a is already registered", RegisterGenerator("test-a", &configuredGenerator{}).Error())
+	assert.Equal(t, "Generator needs a name", RegisterGenerator("", nopGenerator{}).Error())
+	assert.Equal(t, "Generator test-c is nil", RegisterGenerator("test-c", nil).Error())
+}
@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"text/template"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/config"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
+	"github.com/MarcGrol/golangAnnotations/generator/event"
+	"github.com/MarcGrol/golangAnnotations/generator/eventService"
+	"github.com/MarcGrol/golangAnnotations/generator/filegen"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
+	"github.com/MarcGrol/golangAnnotations/generator/plugin"
+	"github.com/MarcGrol/golangAnnotations/generator/repository"
+	"github.com/MarcGrol/golangAnnotations/generator/rest"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/MarcGrol/golangAnnotations/parser"
+)
+
+// Options determine how the sources of a package are parsed and validated
+type Options struct {
+	TypeCheck         bool     // resolve types using the type-checker: cannot be combined with CacheDir
+	BuildTags         []string // build-tags to consider satisfied
+	CacheDir          string   // directory to cache parsed sources in: caching is disabled when empty
+	ToolIdentity      string   // keys the cache, see ToolIdentity: the executable is hashed once when empty
+	StrictAnnotations bool     // fail on invalid annotations instead of warning
+}
+
+// Run generates the code for the sources in inputDir with the registered generators: the built-in
+// generators are registered when needed, use Configure to add the plugins of a project-config
+func Run(inputDir string, options Options) error {
+	err := RegisterBuiltins()
+	if err != nil {
+		return err
+	}
+
+	if options.TypeCheck && options.CacheDir != "" {
+		return fmt.Errorf("Type-check cannot be combined with a cache-dir: the type-checked parser does not use the cache")
+	}
+
+	p := parser.New(options.BuildTags...)
+	if options.CacheDir != "" {
+		identity := options.ToolIdentity
+		if identity == "" {
+			identity = defaultToolIdentity()
+		}
+		p = parser.NewCached(options.CacheDir, identity, options.BuildTags...)
+	}
+	if options.TypeCheck {
+		p = parser.NewTypeChecked(options.BuildTags...)
+	}
+	parsedSources, err := p.ParseSourceDir(inputDir, "^.*.go$", filegen.ExcludeMatchPattern())
+	if err != nil {
+		return fmt.Errorf("Error parsing golang sources in %s:%s", inputDir, err)
+	}
+	err = ValidateAnnotations(parsedSources, options.StrictAnnotations)
+	if err != nil {
+		return err
+	}
+
+	marshalled, err := json.MarshalIndent(parsedSources, "", "\t")
+	if err != nil {
+		return err
+	}
+	targetFilename := filegen.Prefixed(inputDir + "/" + "ast.json")
+	existing, err := ioutil.ReadFile(targetFilename)
+	if err != nil || !bytes.Equal(existing, marshalled) {
+		err = ioutil.WriteFile(targetFilename, marshalled, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return runAllGenerators(inputDir, parsedSources)
+}
+
+// Configure registers the generators and sets the template-dir, as given or from the project-config:
+// without configFilename, the project-config is looked up from the working directory
+func Configure(configFilename string, templateDir string) error {
+	cfg := config.Config{}
+	if configFilename == "" {
+		configFilename, _ = config.Find(".")
+	}
+	if configFilename != "" {
+		var err error
+		cfg, err = config.Load(configFilename)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := registerGenerators(cfg)
+	if err != nil {
+		return fmt.Errorf("Error registering generators of config %s:%s", configFilename, err)
+	}
+
+	if templateDir == "" {
+		templateDir = cfg.TemplateDir
+	}
+	if templateDir != "" {
+		info, err := os.Stat(templateDir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("Template-dir %s is not a directory", templateDir)
+		}
+	}
+	generationUtil.SetTemplateDir(templateDir)
+	return nil
+}
+
+// RegisterBuiltins registers the built-in generators, with their template-functions: registering these again has no effect
+func RegisterBuiltins() error {
+	builtins := map[string]generationUtil.Generator{
+		"event":         event.NewGenerator(),
+		"event-service": eventService.NewGenerator(),
+		"json-helpers":  jsonHelpers.NewGenerator(),
+		"rest":          rest.NewGenerator(),
+		"repository":    repository.NewGenerator(),
+	}
+	for name, g := range builtins {
+		err := generationUtil.RegisterGenerator(name, g)
+		if err != nil {
+			return err
+		}
+	}
+	for _, funcMap := range []template.FuncMap{
+		event.TemplateFuncs(),
+		eventService.TemplateFuncs(),
+		jsonHelpers.TemplateFuncs(),
+		repository.TemplateFuncs(),
+		rest.TemplateFuncs(),
+	} {
+		generationUtil.RegisterTemplateFuncs(funcMap)
+	}
+	return nil
+}
+
+// registerGenerators registers the built-in generators and the plugins of the project-config
+func registerGenerators(cfg config.Config) error {
+	err := RegisterBuiltins()
+	if err != nil {
+		return err
+	}
+	for _, p := range cfg.Plugins {
+		err := generationUtil.RegisterGenerator(p.Name, plugin.NewGenerator(p))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAnnotations reports the invalid annotations, that the parser did not attach to the parsed sources:
+// these are fatal when strict. Conflicting annotations are always fatal,
+// so that no file is generated from an ambiguous model.
+func ValidateAnnotations(parsedSources model.ParsedSources, strict bool) error {
+	registry := annotation.NewRegistry(annotation.RegisteredDescriptors())
+
+	problems := annotation.ValidateAnnotations(registry, parsedSources)
+	for _, problem := range problems {
+		if !strict {
+			problem.Severity = diagnostics.SeverityWarning
+		}
+		diagnostics.Report(problem)
+	}
+	if strict && len(problems) > 0 {
+		return fmt.Errorf("Found %d invalid annotation(s)", len(problems))
+	}
+
+	conflicts := annotation.ValidateAnnotationRules(registry, parsedSources)
+	for _, conflict := range conflicts {
+		diagnostics.Report(conflict)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("Found %d conflicting annotation(s)", len(conflicts))
+	}
+	return nil
+}
+
+func runAllGenerators(inputDir string, parsedSources model.ParsedSources) error {
+	for _, name := range generationUtil.GetGeneratorNames() {
+		g, _ := generationUtil.GetGenerator(name)
+		err := g.Generate(inputDir, parsedSources)
+		if err != nil {
+			return fmt.Errorf("Error generating module %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var (
+	defaultToolIdentityOnce sync.Once
+	defaultIdentity         string
+)
+
+func defaultToolIdentity() string {
+	defaultToolIdentityOnce.Do(func() {
+		defaultIdentity = ToolIdentity("")
+	})
+	return defaultIdentity
+}
+
+// ToolIdentity keys the cache on the executable itself, so that a rebuilt tool never uses entries of another build:
+// it reads the whole executable, so compute it once per run
+func ToolIdentity(toolVersion string) string {
+	executable, err := os.Executable()
+	if err != nil {
+		return toolVersion
+	}
+	content, err := ioutil.ReadFile(executable)
+	if err != nil {
+		return toolVersion
+	}
+	return fmt.Sprintf("%s-%x", toolVersion, sha256.Sum256(content))
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTwice(t *testing.T) {
+	assert.NoError(t, Configure("", ""))
+	assert.NoError(t, Configure("", ""))
+	assert.NoError(t, RegisterBuiltins())
+
+	assert.Equal(t, []string{"event", "event-service", "json-helpers", "repository", "rest"}, generationUtil.GetGeneratorNames())
+}
+
+func TestRun(t *testing.T) {
+	inputDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+
+	err = ioutil.WriteFile(filepath.Join(inputDir, "person.go"), []byte("package person\n\nfunc Greet(name string) string {\n\treturn name\n}\n"), 0644)
+	assert.NoError(t, err)
+
+	err = Run(inputDir, Options{})
+	assert.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(inputDir, "gen_ast.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"name": "Greet"`)
+
+	// a second run in the same process registers nothing new
+	err = Run(inputDir, Options{StrictAnnotations: true})
+	assert.NoError(t, err)
+}
+
+func TestRunReportsInvalidAnnotations(t *testing.T) {
+	inputDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+
+	err = ioutil.WriteFile(filepath.Join(inputDir, "person.go"), []byte("package person\n\n// @RestOperaton( method = \"GET\" )\nfunc Greet(name string) string {\n\treturn name\n}\n"), 0644)
+	assert.NoError(t, err)
+
+	// a warning only, unless strict
+	assert.NoError(t, Run(inputDir, Options{}))
+	err = Run(inputDir, Options{StrictAnnotations: true})
+	assert.Error(t, err)
+	assert.Equal(t, "Found 1 invalid annotation(s)", err.Error())
+}
+
+func TestRunRejectsCacheWithTypeCheck(t *testing.T) {
+	err := Run(".", Options{TypeCheck: true, CacheDir: "cache"})
+	assert.Error(t, err)
+	assert.Equal(t, "Type-check cannot be combined with a cache-dir: the type-checked parser does not use the cache", err.Error())
+}
+
+func TestBuiltinsReferToExistingAnnotations(t *testing.T) {
+	assert.NoError(t, RegisterBuiltins())
+
+	// generators refer to the annotations of other generators by name
+	names := map[string]bool{}
+	for _, descriptor := range annotation.RegisteredDescriptors() {
+		names[descriptor.Name] = true
+	}
+	for _, descriptor := range annotation.RegisteredDescriptors() {
+		for _, name := range append(append([]string{}, descriptor.ExclusiveWith...), descriptor.RequiresEnclosing...) {
+			assert.True(t, names[name], "@%s refers to unknown annotation @%s", descriptor.Name, name)
+		}
+	}
+}
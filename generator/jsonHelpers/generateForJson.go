@@ -2,12 +2,13 @@ package jsonHelpers
 
 import (
 	"fmt"
-	"log"
+	"go/ast"
 	"strings"
 	"text/template"
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers/jsonAnnotation"
@@ -17,6 +18,8 @@ import (
 type Generator struct {
 }
 
+var annotationRegistry = annotation.NewRegistry(jsonAnnotation.Get())
+
 func NewGenerator() generationUtil.Generator {
 	return &Generator{}
 }
@@ -36,6 +39,9 @@ type jsonContext struct {
 }
 
 func generate(inputDir string, enums []model.Enum, structs []model.Struct) error {
+	if len(enums) == 0 && len(structs) == 0 {
+		return nil
+	}
 
 	packageName, err := generationUtil.GetPackageNameForEnumsOrStructs(enums, structs)
 	if err != nil {
@@ -55,6 +61,9 @@ func generate(inputDir string, enums []model.Enum, structs []model.Struct) error
 	jsonStructs := make([]model.Struct, 0, len(structs))
 	for _, aStruct := range structs {
 		if IsJSONStruct(aStruct) {
+			if len(aStruct.TypeParams) > 0 {
+				return diagnostics.Errorf(aStruct.Position, "Json-struct %s is generic: generic types are not supported", aStruct.Name)
+			}
 			jsonStructs = append(jsonStructs, aStruct)
 		}
 	}
@@ -96,8 +105,7 @@ func doGenerate(packageName string, jsonEnums []model.Enum, jsonStructs []model.
 		if len(data.Enums) > 0 || len(data.Structs) > 0 {
 			err := generationUtil.GenerateFileFromTemplate(data, packageName, "json-enums", jsonHelpersTemplate, customTemplateFuncs, target)
 			if err != nil {
-				log.Fatalf("Error generating wrappers for enums (%s)", err)
-				return err
+				return diagnostics.Errorf(jsonPosition(data), "Error generating json-helpers for %s: %s", fn, err)
 			}
 		}
 	}
@@ -126,6 +134,11 @@ func getFilenamesWithTypeNames(jsonEnums []model.Enum, jsonStructs []model.Struc
 	return filenameMap
 }
 
+// TemplateFuncs returns the functions available in the templates of the generator
+func TemplateFuncs() template.FuncMap {
+	return customTemplateFuncs
+}
+
 var customTemplateFuncs = template.FuncMap{
 	"HasAlternativeName": hasAlternativeName,
 	"GetAlternativeName": getAlternativeName,
@@ -133,33 +146,34 @@ var customTemplateFuncs = template.FuncMap{
 	"HasDefaultValue":    hasDefaultValue,
 	"GetDefaultValue":    getDefaultValue,
 	"HasSlices":          hasSlices,
+	"GetSliceFields":     getSliceFields,
+	"GetEmptySlice":      getEmptySlice,
+	"GetJSONName":        generationUtil.GetJSONName,
+	"IsJSONIgnored":      generationUtil.IsJSONIgnored,
+	"IsJSONOmitEmpty":    generationUtil.IsJSONOmitEmpty,
 }
 
 func IsJSONEnum(e model.Enum) bool {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(e.DocLines, jsonAnnotation.TypeEnum)
+	_, ok := annotationRegistry.FindAnnotation(e.Annotations, e.DocLines, jsonAnnotation.TypeEnum)
 	return ok
 }
 
 func IsJSONEnumStripped(e model.Enum) bool {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(e.DocLines, jsonAnnotation.TypeEnum); ok {
-		return ann.Attributes[jsonAnnotation.ParamStripped] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(e.Annotations, e.DocLines, jsonAnnotation.TypeEnum); ok {
+		return ann.GetBool(jsonAnnotation.ParamStripped)
 	}
 	return false
 }
 
 func IsJSONEnumTolerant(e model.Enum) bool {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(e.DocLines, jsonAnnotation.TypeEnum); ok {
-		return ann.Attributes[jsonAnnotation.ParamTolerant] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(e.Annotations, e.DocLines, jsonAnnotation.TypeEnum); ok {
+		return ann.GetBool(jsonAnnotation.ParamTolerant)
 	}
 	return false
 }
 
 func GetJSONEnumBase(e model.Enum) string {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(e.DocLines, jsonAnnotation.TypeEnum); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(e.Annotations, e.DocLines, jsonAnnotation.TypeEnum); ok {
 		return ann.Attributes[jsonAnnotation.ParamBase]
 	}
 	return ""
@@ -170,8 +184,7 @@ func HasJSONEnumBase(e model.Enum) bool {
 }
 
 func GetJSONEnumDefault(e model.Enum) string {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(e.DocLines, jsonAnnotation.TypeEnum); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(e.Annotations, e.DocLines, jsonAnnotation.TypeEnum); ok {
 		return ann.Attributes[jsonAnnotation.ParamDefault]
 	}
 	return ""
@@ -212,16 +225,63 @@ func lowerInitial(s string) string {
 }
 
 func IsJSONStruct(s model.Struct) bool {
-	annotations := annotation.NewRegistry(jsonAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(s.DocLines, jsonAnnotation.TypeStruct)
+	_, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, jsonAnnotation.TypeStruct)
 	return ok
 }
 
 func hasSlices(s model.Struct) bool {
+	return len(getSliceFields(s)) > 0
+}
+
+func getSliceFields(s model.Struct) []model.Field {
+	sliceFields := []model.Field{}
 	for _, f := range s.Fields {
-		if f.IsSlice {
+		if isSliceField(f) && !generationUtil.IsJSONIgnored(f) {
+			sliceFields = append(sliceFields, f)
+		}
+	}
+	for _, f := range s.PromotedFields {
+		// Only exported fields end up in json, and fields reached through an embedded pointer may be nil
+		if isSliceField(f) && !generationUtil.IsJSONIgnored(f) && ast.IsExported(f.Name) && !isPromotedViaPointer(f) {
+			sliceFields = append(sliceFields, f)
+		}
+	}
+	return sliceFields
+}
+
+func isPromotedViaPointer(f model.Field) bool {
+	for _, via := range f.PromotedVia {
+		if strings.HasPrefix(via, "*") {
 			return true
 		}
 	}
 	return false
 }
+
+func isSliceField(f model.Field) bool {
+	return f.IsSlice || isNamedSliceField(f)
+}
+
+// isNamedSliceField recognizes fields like 'Tags IDs' with 'type IDs []string': requires type-checked parsing or the typedef in the same package
+func isNamedSliceField(f model.Field) bool {
+	return !f.IsPointer && f.Type != nil && f.Type.Kind == model.KindNamed && f.Type.Underlying == model.KindSlice
+}
+
+func getEmptySlice(f model.Field) string {
+	if isNamedSliceField(f) {
+		return fmt.Sprintf("%s{}", f.TypeName)
+	}
+	pointer := ""
+	if f.IsPointer {
+		pointer = "*"
+	}
+	return fmt.Sprintf("[]%s%s{}", pointer, f.TypeName)
+}
+
+// jsonPosition is where to report a problem with a generated file: at the first enum or struct it is based on
+func jsonPosition(data jsonContext) model.Position {
+	if len(data.Enums) > 0 {
+		return data.Enums[0].Position
+	}
+	return data.Structs[0].Position
+}
@@ -3,6 +3,7 @@ package jsonHelpers
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
@@ -58,6 +59,37 @@ func TestGenerateForJson(t *testing.T) {
 					TypeName: "ColorType",
 					IsSlice:  true,
 				},
+				{
+					Name:     "Ids",
+					TypeName: "IDs",
+					Type:     &model.Type{Kind: model.KindNamed, Name: "IDs", Underlying: model.KindSlice},
+				},
+				{
+					Name:     "Notes",
+					TypeName: "string",
+					IsSlice:  true,
+					Tags:     []model.Tag{{Key: "json", Name: "notes", Options: []string{"omitempty"}}},
+				},
+				{
+					Name:     "Cache",
+					TypeName: "string",
+					IsSlice:  true,
+					Tags:     []model.Tag{{Key: "json", Name: "-"}},
+				},
+			},
+			PromotedFields: []model.Field{
+				{
+					Name:        "Labels",
+					TypeName:    "string",
+					IsSlice:     true,
+					PromotedVia: []string{"Base"},
+				},
+				{
+					Name:        "Items",
+					TypeName:    "string",
+					IsSlice:     true,
+					PromotedVia: []string{"*Other"},
+				},
 			},
 		},
 	}
@@ -81,6 +113,15 @@ func TestGenerateForJson(t *testing.T) {
 
 	assert.Contains(t, string(data), `func (data *ColoredThing) UnmarshalJSON(b []byte) error {`)
 	assert.Contains(t, string(data), `func (data ColoredThing) MarshalJSON() ([]byte, error) {`)
+	assert.Contains(t, string(data), `// written as "OtherColors": [] instead of null`)
+	assert.Contains(t, string(data), `raw.OtherColors = []ColorType{}`)
+	assert.Contains(t, string(data), `raw.Ids = IDs{}`)
+	assert.Contains(t, string(data), `raw.Labels = []string{}`)
+	assert.NotContains(t, string(data), `raw.Items`)
+	assert.NotContains(t, string(data), `raw.Cache`)
+	// an omitted empty slice is not written at all
+	assert.NotContains(t, string(data), `"notes": []`)
+	assert.Equal(t, 1, strings.Count(string(data), `raw.Notes = []string{}`))
 
 }
 
@@ -103,3 +144,46 @@ func TestIsJsonStruct(t *testing.T) {
 	}
 	assert.True(t, IsJSONStruct(s))
 }
+
+func TestGenerateForGenericJsonStruct(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			PackageName: "testData",
+			Filename:    "example.go",
+			DocLines:    []string{`// @JsonStruct()`},
+			Name:        "Page",
+			TypeParams:  []model.TypeParam{{Name: "T", Constraint: "any"}},
+			Fields: []model.Field{
+				{
+					Name:        "Items",
+					TypeName:    "T",
+					IsSlice:     true,
+					IsTypeParam: true,
+				},
+			},
+		},
+	}
+
+	err := NewGenerator().Generate("./testData/", model.ParsedSources{Structs: s})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Page")
+
+	_, err = os.Stat(filegen.Prefixed("./testData/example_json.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetSliceFieldsOfPromotedFieldWithoutName(t *testing.T) {
+	s := model.Struct{
+		PromotedFields: []model.Field{
+			{TypeName: "string", IsSlice: true, PromotedVia: []string{"Base"}},
+			{Name: "labels", TypeName: "string", IsSlice: true, PromotedVia: []string{"Base"}},
+			{Name: "Labels", TypeName: "string", IsSlice: true, PromotedVia: []string{"Base"}},
+		},
+	}
+	sliceFields := getSliceFields(s)
+	assert.Len(t, sliceFields, 1)
+	assert.Equal(t, "Labels", sliceFields[0].Name)
+}
@@ -14,14 +14,30 @@ const (
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
-			Name:       TypeEnum,
-			ParamNames: []string{ParamStripped, ParamTolerant, ParamBase, ParamDefault},
-			Validator:  validateEnumAnnotation,
+			Name:        TypeEnum,
+			Description: "Generates json-marshalling of the enum by name instead of by value",
+			ParamNames:  []string{ParamStripped, ParamTolerant, ParamBase, ParamDefault},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamStripped: annotation.ParamTypeBool,
+				ParamTolerant: annotation.ParamTypeBool,
+			},
+			ParamDescriptions: map[string]string{
+				ParamStripped: "Strip the base from the names of the values",
+				ParamTolerant: "Also accept the names without the base, or with it when stripped",
+				ParamBase:     "Common prefix of the names of the values",
+				ParamDefault:  "Literal, without the base, to use for unknown names and values",
+			},
+			Unique:    true,
+			Targets:   []annotation.Target{annotation.TargetEnum},
+			Validator: validateEnumAnnotation,
 		},
 		{
-			Name:       TypeStruct,
-			ParamNames: []string{},
-			Validator:  validateStructAnnotation,
+			Name:        TypeStruct,
+			Description: "Generates json-helpers for the struct",
+			ParamNames:  []string{},
+			Unique:      true,
+			Targets:     []annotation.Target{annotation.TargetStruct},
+			Validator:   validateStructAnnotation,
 		}}
 }
 
@@ -4,7 +4,7 @@ const jsonHelpersTemplate = `// Generated automatically by golangAnnotations: do
 
 package {{.PackageName}}
 
-import "encoding/json"
+{{block "imports" .}}import "encoding/json"{{end}}
 
 {{range .Enums}}
 {{$enum := .}}
@@ -79,12 +79,13 @@ func (r *{{.Name}}) UnmarshalJSON(data []byte) error {
 func (data {{.Name}}) MarshalJSON() ([]byte, error) {
     type alias {{.Name}}
     var raw = alias(data)
-    {{range .Fields -}}
-        {{if .IsSlice -}}
-			if raw.{{.Name}} == nil {
-				raw.{{.Name}} = []{{if .IsPointer}}*{{end}}{{.TypeName}}{}
-			}
-        {{end -}}
+    {{range GetSliceFields . -}}
+    {{if not (IsJSONOmitEmpty .) -}}
+		// written as "{{GetJSONName .}}": [] instead of null
+		if raw.{{.Name}} == nil {
+			raw.{{.Name}} = {{GetEmptySlice .}}
+		}
+    {{end -}}
     {{end -}}
 
     return json.Marshal(raw)
@@ -96,12 +97,10 @@ func (data *{{.Name}}) UnmarshalJSON(b []byte) error {
     var raw alias
     err := json.Unmarshal(b, &raw)
 
-    {{range .Fields -}}
-        {{if .IsSlice -}}
+    {{range GetSliceFields . -}}
     if raw.{{.Name}} == nil {
-        raw.{{.Name}} = []{{if .IsPointer}}*{{end}}{{.TypeName}}{}
+        raw.{{.Name}} = {{GetEmptySlice .}}
     }
-        {{end -}}
     {{end -}}
 
     *data = {{.Name}}(raw)
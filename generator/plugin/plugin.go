@@ -0,0 +1,149 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/config"
+	"github.com/MarcGrol/golangAnnotations/generator/filegen"
+	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// Response is what a plugin writes to stdout: the files to generate, relative to the input-dir
+type Response struct {
+	Files []File `json:"files"`
+}
+
+type File struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+type Generator struct {
+	plugin config.Plugin
+}
+
+// NewGenerator wraps the plugin as generator: the plugin runs in the input-dir, receives the parsed sources as json
+// on stdin and the input-dir in environment-variable GOLANGANNOTATIONS_INPUT_DIR
+func NewGenerator(plugin config.Plugin) generationUtil.Generator {
+	return &Generator{
+		plugin: plugin,
+	}
+}
+
+func (pg *Generator) GetAnnotations() []annotation.AnnotationDescriptor {
+	descriptors := []annotation.AnnotationDescriptor{}
+	for _, declared := range pg.plugin.Annotations {
+		descriptors = append(descriptors, newDescriptor(declared))
+	}
+	return descriptors
+}
+
+func newDescriptor(declared config.Annotation) annotation.AnnotationDescriptor {
+	paramTypes := map[string]annotation.ParamType{}
+	for name, paramType := range declared.ParamTypes {
+		paramTypes[strings.ToLower(name)] = annotation.ParamType(paramType)
+	}
+	required := lowered(declared.Required)
+	targets := []annotation.Target{}
+	for _, target := range declared.Targets {
+		targets = append(targets, annotation.Target(target))
+	}
+	return annotation.AnnotationDescriptor{
+		Name:              declared.Name,
+		Description:       declared.Description,
+		ParamNames:        lowered(declared.Params),
+		ParamTypes:        paramTypes,
+		ParamDescriptions: declared.ParamDescriptions,
+		Required:          required,
+		Unique:            declared.Unique,
+		ExclusiveWith:     declared.ExclusiveWith,
+		RequiresEnclosing: declared.RequiresEnclosing,
+		Targets:           targets,
+		Validator: func(annot annotation.Annotation) bool {
+			for _, name := range required {
+				if _, ok := annot.Attributes[name]; !ok {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// lowered returns the param-names the way the parser stores attribute-names
+func lowered(names []string) []string {
+	result := []string{}
+	for _, name := range names {
+		result = append(result, strings.ToLower(name))
+	}
+	return result
+}
+
+func (pg *Generator) Generate(inputDir string, parsedSources model.ParsedSources) error {
+	request, err := json.Marshal(parsedSources)
+	if err != nil {
+		return err
+	}
+	// absolute, so that the plugin can use it after changing its working directory
+	absInputDir, err := filepath.Abs(inputDir)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(pg.plugin.Command, pg.plugin.Args...)
+	cmd.Dir = absInputDir
+	cmd.Env = append(os.Environ(), "GOLANGANNOTATIONS_INPUT_DIR="+absInputDir)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Error running plugin %s:%s %s", pg.plugin.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	response := Response{}
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return fmt.Errorf("Error parsing response of plugin %s:%s", pg.plugin.Name, err)
+	}
+
+	// Validate all files before writing any, so that a faulty plugin leaves the package as it was
+	for _, file := range response.Files {
+		err = validateFilename(file.Filename)
+		if err != nil {
+			return fmt.Errorf("Invalid response of plugin %s:%s", pg.plugin.Name, err)
+		}
+	}
+	for _, file := range response.Files {
+		err = generationUtil.WriteGeneratedFile([]byte(file.Content), "plugin "+pg.plugin.Name, filepath.Join(inputDir, file.Filename))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFilename only accepts generated files within the input-dir, recognizable by their prefix
+func validateFilename(filename string) error {
+	if filename == "" {
+		return fmt.Errorf("File without filename")
+	}
+	cleaned := filepath.Clean(filename)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("File %s is outside the input-dir", filename)
+	}
+	prefix := filegen.Prefixed("")
+	if !strings.HasPrefix(filepath.Base(cleaned), prefix) {
+		return fmt.Errorf("File %s lacks prefix %s", filename, prefix)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/config"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHelperPlugin is not a real test: it is the plugin that the other tests run, as a re-executed test-binary
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("GOLANGANNOTATIONS_TEST_PLUGIN") == "" {
+		return
+	}
+	parsedSources := model.ParsedSources{}
+	err := json.NewDecoder(os.Stdin).Decode(&parsedSources)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s", err)
+		os.Exit(1)
+	}
+	switch os.Getenv("GOLANGANNOTATIONS_TEST_PLUGIN") {
+	case "fail":
+		fmt.Fprintf(os.Stderr, "Plugin failure")
+		os.Exit(2)
+	case "escape":
+		json.NewEncoder(os.Stdout).Encode(Response{Files: []File{{Filename: "../gen_escaped.go", Content: "package x\n"}}})
+	default:
+		files := []File{}
+		for _, s := range parsedSources.Structs {
+			files = append(files, File{
+				Filename: "gen_" + s.Name + ".txt",
+				Content:  fmt.Sprintf("%s in %s", s.Name, os.Getenv("GOLANGANNOTATIONS_INPUT_DIR")),
+			})
+		}
+		json.NewEncoder(os.Stdout).Encode(Response{Files: files})
+	}
+	os.Exit(0)
+}
+
+func helperPlugin(t *testing.T, mode string) config.Plugin {
+	os.Setenv("GOLANGANNOTATIONS_TEST_PLUGIN", mode)
+	return config.Plugin{
+		Name:    "helper",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperPlugin"},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	defer os.Unsetenv("GOLANGANNOTATIONS_TEST_PLUGIN")
+	inputDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+
+	parsedSources := model.ParsedSources{Structs: []model.Struct{{Name: "Person"}}}
+	err = NewGenerator(helperPlugin(t, "ok")).Generate(inputDir, parsedSources)
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(inputDir, "gen_Person.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Person in "+inputDir, string(content))
+}
+
+func TestGenerateInRelativeInputDir(t *testing.T) {
+	defer os.Unsetenv("GOLANGANNOTATIONS_TEST_PLUGIN")
+	inputDir, err := ioutil.TempDir(".", "testData")
+	assert.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+
+	parsedSources := model.ParsedSources{Structs: []model.Struct{{Name: "Person"}}}
+	err = NewGenerator(helperPlugin(t, "ok")).Generate(inputDir, parsedSources)
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(inputDir, "gen_Person.txt"))
+	assert.NoError(t, err)
+	absInputDir, err := filepath.Abs(inputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Person in "+absInputDir, string(content))
+}
+
+func TestGenerateFailures(t *testing.T) {
+	defer os.Unsetenv("GOLANGANNOTATIONS_TEST_PLUGIN")
+	inputDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(inputDir)
+
+	err = NewGenerator(helperPlugin(t, "fail")).Generate(inputDir, model.ParsedSources{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Plugin failure")
+
+	err = NewGenerator(helperPlugin(t, "escape")).Generate(inputDir, model.ParsedSources{})
+	assert.Equal(t, "Invalid response of plugin helper:File ../gen_escaped.go is outside the input-dir", err.Error())
+}
+
+func TestValidateFilename(t *testing.T) {
+	assert.NoError(t, validateFilename("gen_a.go"))
+	assert.NoError(t, validateFilename("sub/gen_a.go"))
+	assert.Error(t, validateFilename(""))
+	assert.Error(t, validateFilename("/tmp/gen_a.go"))
+	assert.Error(t, validateFilename("sub/../../gen_a.go"))
+	assert.Equal(t, "File a.go lacks prefix gen_", validateFilename("a.go").Error())
+}
+
+func TestGetAnnotations(t *testing.T) {
+	descriptors := NewGenerator(config.Plugin{
+		Name: "graphql",
+		Annotations: []config.Annotation{
+			{Name: "GraphqlType", Params: []string{"Name", "public"}, ParamTypes: map[string]string{"public": "bool"}, Required: []string{"Name"}, Targets: []string{"struct"}},
+		},
+	}).GetAnnotations()
+
+	assert.Equal(t, []annotation.Target{annotation.TargetStruct}, descriptors[0].Targets)
+
+	registry := annotation.NewRegistry(descriptors)
+	ann, ok := registry.ResolveAnnotation(`// @GraphqlType( name = "Person", public = true )`)
+	assert.True(t, ok)
+	assert.True(t, ann.GetBool("public"))
+	_, ok = registry.ResolveAnnotation(`// @GraphqlType( public = true )`)
+	assert.False(t, ok)
+}
@@ -2,12 +2,11 @@ package repository
 
 import (
 	"fmt"
-	"log"
-	"strings"
 	"text/template"
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/generator/repository/repositoryAnnotation"
@@ -17,6 +16,8 @@ import (
 type Generator struct {
 }
 
+var annotationRegistry = annotation.NewRegistry(repositoryAnnotation.Get())
+
 func NewGenerator() generationUtil.Generator {
 	return &Generator{}
 }
@@ -30,6 +31,9 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 }
 
 func generateRepo(inputDir string, structs []model.Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if err != nil {
@@ -44,14 +48,18 @@ func generateRepo(inputDir string, structs []model.Struct) error {
 			target := filegen.Prefixed(fmt.Sprintf("%s/%s.go", targetDir, toFirstLower(repository.Name)))
 			err = generationUtil.GenerateFileFromTemplate(repository, fmt.Sprintf("%s.%s", repository.PackageName, repository.Name), "repository", repositoryTemplate, customTemplateFuncs, target)
 			if err != nil {
-				log.Fatalf("Error generating repository %s: %s", repository.Name, err)
-				return err
+				return diagnostics.Errorf(repository.Position, "Error generating repository %s: %s", repository.Name, err)
 			}
 		}
 	}
 	return nil
 }
 
+// TemplateFuncs returns the functions available in the templates of the generator
+func TemplateFuncs() template.FuncMap {
+	return customTemplateFuncs
+}
+
 var customTemplateFuncs = template.FuncMap{
 	"IsRepository":              IsRepository,
 	"AggregateNameConst":        AggregateNameConst,
@@ -74,8 +82,7 @@ var customTemplateFuncs = template.FuncMap{
 }
 
 func IsRepository(s model.Struct) bool {
-	annotations := annotation.NewRegistry(repositoryAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(s.DocLines, repositoryAnnotation.TypeRepository)
+	_, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, repositoryAnnotation.TypeRepository)
 	return ok
 }
 
@@ -92,16 +99,14 @@ func UpperAggregateName(s model.Struct) string {
 }
 
 func GetAggregateName(s model.Struct) string {
-	annotations := annotation.NewRegistry(repositoryAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, repositoryAnnotation.TypeRepository); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, repositoryAnnotation.TypeRepository); ok {
 		return ann.Attributes[repositoryAnnotation.ParamAggregate]
 	}
 	return ""
 }
 
 func GetPackageName(s model.Struct) string {
-	annotations := annotation.NewRegistry(repositoryAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, repositoryAnnotation.TypeRepository); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, repositoryAnnotation.TypeRepository); ok {
 		packageName := ann.Attributes[repositoryAnnotation.ParamPackage]
 		if packageName != "" {
 			return packageName
@@ -123,8 +128,7 @@ func ModelPackageName(s model.Struct) string {
 }
 
 func GetModelName(s model.Struct) string {
-	annotations := annotation.NewRegistry(repositoryAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, repositoryAnnotation.TypeRepository); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, repositoryAnnotation.TypeRepository); ok {
 		m := ann.Attributes[repositoryAnnotation.ParamModel]
 		if m != "" {
 			return m
@@ -174,11 +178,10 @@ func HasMethodPurgeAll(s model.Struct) bool {
 }
 
 func HasMethod(s model.Struct, methodName string) bool {
-	annotations := annotation.NewRegistry(repositoryAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, repositoryAnnotation.TypeRepository); ok {
-		methods := strings.Split(ann.Attributes[repositoryAnnotation.ParamMethods], ",")
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, repositoryAnnotation.TypeRepository); ok {
+		methods, _ := ann.GetStrings(repositoryAnnotation.ParamMethods)
 		for _, method := range methods {
-			if strings.TrimSpace(method) == methodName {
+			if method == methodName {
 				return true
 			}
 		}
@@ -4,7 +4,7 @@ const repositoryTemplate = `// Generated automatically by golangAnnotations: do
 
 package {{.PackageName}}
 
-import "golang.org/x/net/context"
+{{block "imports" .}}import "golang.org/x/net/context"{{end}}
 
 {{if HasMethodFind . -}}
 var Find{{UpperModelName .}}OnUID = DefaultFind{{UpperModelName .}}OnUID
@@ -14,9 +14,22 @@ const (
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
-			Name:       TypeRepository,
-			ParamNames: []string{ParamAggregate, ParamPackage, ParamModel, ParamMethods},
-			Validator:  validateRepositoryAnnotation,
+			Name:        TypeRepository,
+			Description: "Generates a repository that stores and retrieves the events of an aggregate",
+			ParamNames:  []string{ParamAggregate, ParamPackage, ParamModel, ParamMethods},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamMethods: annotation.ParamTypeList,
+			},
+			ParamDescriptions: map[string]string{
+				ParamAggregate: "Aggregate to store the events of",
+				ParamPackage:   "Package of the events: <aggregate>Events by default",
+				ParamModel:     "Model that is built from the events: the aggregate by default",
+				ParamMethods:   "Methods to generate: find, filterByEvent, filterByMoment, findStates, exists, allAggregateUIDs, allAggregates, purgeOnEventUIDs, purgeOnEventType and purgeAll",
+			},
+			Required:  []string{ParamAggregate, ParamMethods},
+			Unique:    true,
+			Targets:   []annotation.Target{annotation.TargetStruct},
+			Validator: validateRepositoryAnnotation,
 		},
 	}
 }
@@ -2,13 +2,14 @@ package rest
 
 import (
 	"fmt"
-	"log"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
 
 	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
 	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
@@ -18,6 +19,8 @@ import (
 type Generator struct {
 }
 
+var annotationRegistry = annotation.NewRegistry(restAnnotation.Get())
+
 func NewGenerator() generationUtil.Generator {
 	return &Generator{}
 }
@@ -31,6 +34,9 @@ func (eg *Generator) Generate(inputDir string, parsedSource model.ParsedSources)
 }
 
 func generate(inputDir string, structs []model.Struct) error {
+	if len(structs) == 0 {
+		return nil
+	}
 
 	packageName, err := generationUtil.GetPackageNameForStructs(structs)
 	if err != nil {
@@ -43,6 +49,15 @@ func generate(inputDir string, structs []model.Struct) error {
 
 	for _, service := range structs {
 		if IsRestService(service) {
+			if len(service.TypeParams) > 0 {
+				return diagnostics.Errorf(service.Position, "Rest-service %s is generic: generic receivers are not supported", service.Name)
+			}
+
+			err = validateRestOperations(service)
+			if err != nil {
+				return err
+			}
+
 			err = generateHttpService(targetDir, packageName, service)
 			if err != nil {
 				return err
@@ -67,12 +82,60 @@ func generate(inputDir string, structs []model.Struct) error {
 	return nil
 }
 
+// validateRestOperations rejects parameters that cannot be filled from an http-request and value-receivers
+// that would make the handlers operate on a copy of the service: a service without fields has no state to copy
+func validateRestOperations(service model.Struct) error {
+	for _, o := range service.Operations {
+		if !IsRestOperation(*o) {
+			continue
+		}
+		if o.HasValueReceiver() && len(service.Fields) > 0 {
+			return diagnostics.Errorf(o.Position, "Rest-operation %s.%s has a value-receiver: use a pointer-receiver so it does not operate on a copy of the service",
+				service.Name, o.Name)
+		}
+		for _, arg := range o.InputArgs {
+			if IsContextArg(arg) || IsRequestContextArg(arg) || IsUploadArg(arg) {
+				continue
+			}
+			kind := getUnsupportedArgKind(arg)
+			if kind != "" {
+				return diagnostics.Errorf(arg.Position, "Rest-operation %s.%s: %s parameter '%s %s' is not supported",
+					service.Name, o.Name, kind, arg.Name, arg.TypeString())
+			}
+		}
+	}
+	return nil
+}
+
+func getUnsupportedArgKind(arg model.Field) string {
+	if arg.IsVariadic {
+		return "variadic"
+	}
+	elem := getElemType(arg)
+	if elem == nil {
+		return ""
+	}
+	kind := elem.Kind
+	if kind == model.KindNamed && elem.Underlying != "" {
+		// only known when type-checked
+		kind = elem.Underlying
+	}
+	switch kind {
+	case model.KindFunc:
+		return "func-typed"
+	case model.KindChan:
+		return "channel-typed"
+	case model.KindInterface:
+		return "interface-typed"
+	}
+	return ""
+}
+
 func generateHttpService(targetDir, packageName string, service model.Struct) error {
 	target := filegen.Prefixed(fmt.Sprintf("%s/http%s.go", targetDir, ToFirstUpper(service.Name)))
 	err := generationUtil.GenerateFileFromTemplate(service, fmt.Sprintf("%s.%s", service.PackageName, ToFirstUpper(service.Name)), "http-handlers", httpHandlersTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating handlers for service %s: %s", service.Name, err)
-		return err
+		return diagnostics.Errorf(service.Position, "Error generating handlers for service %s: %s", service.Name, err)
 	}
 	return nil
 }
@@ -81,8 +144,7 @@ func generateHttpTestHelpers(targetDir, packageName string, service model.Struct
 	target := filegen.Prefixed(fmt.Sprintf("%s/http%sHelpers_test.go", targetDir, ToFirstUpper(service.Name)))
 	err := generationUtil.GenerateFileFromTemplate(service, fmt.Sprintf("%s.%s", service.PackageName, ToFirstUpper(service.Name)), "test-helpers", testHelpersTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating helpers for service %s: %s", service.Name, err)
-		return err
+		return diagnostics.Errorf(service.Position, "Error generating helpers for service %s: %s", service.Name, err)
 	}
 	return nil
 }
@@ -97,8 +159,7 @@ func generateHttpTestService(targetDir, packageName string, service model.Struct
 
 	err := generationUtil.GenerateFileFromTemplate(service, fmt.Sprintf("%s.%s", service.PackageName, ToFirstUpper(service.Name)), "testService", testServiceTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating testHandler for service %s: %s", service.Name, err)
-		return err
+		return diagnostics.Errorf(service.Position, "Error generating testHandler for service %s: %s", service.Name, err)
 	}
 	return nil
 }
@@ -107,15 +168,22 @@ func generateHttpClient(targetDir, packageName string, service model.Struct) err
 	target := filegen.Prefixed(fmt.Sprintf("%s/httpClientFor%s.go", targetDir, ToFirstUpper(service.Name)))
 	err := generationUtil.GenerateFileFromTemplate(service, fmt.Sprintf("%s.%s", service.PackageName, ToFirstUpper(service.Name)), "http-client", httpClientTemplate, customTemplateFuncs, target)
 	if err != nil {
-		log.Fatalf("Error generating httpClient for service %s: %s", service.Name, err)
-		return err
+		return diagnostics.Errorf(service.Position, "Error generating httpClient for service %s: %s", service.Name, err)
 	}
 	return nil
 }
 
+// TemplateFuncs returns the functions available in the templates of the generator
+func TemplateFuncs() template.FuncMap {
+	return customTemplateFuncs
+}
+
 var customTemplateFuncs = template.FuncMap{
 	"IsRestService":                         IsRestService,
+	"GetConstructorParams":                  generationUtil.GetConstructorParams,
+	"GetConstructorArgs":                    generationUtil.GetConstructorArgs,
 	"ExtractImports":                        ExtractImports,
+	"ExtractHandlerImports":                 ExtractHandlerImports,
 	"GetRestServicePath":                    GetRestServicePath,
 	"GetExtractRequestContextMethod":        GetExtractRequestContextMethod,
 	"IsRestServiceNoValidation":             IsRestServiceNoValidation,
@@ -183,36 +251,31 @@ func SurroundWithBackTicks(body string) string {
 }
 
 func IsRestService(s model.Struct) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService)
+	_, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService)
 	return ok
 }
 
 func IsRestOperationTransactional(s model.Struct, o model.Operation) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		return ann.Attributes[restAnnotation.ParamTransactional] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return ann.GetBool(restAnnotation.ParamTransactional)
 	}
 	return false
 }
 
 func IsRestServiceUnprotected(s model.Struct) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService)
-	return ok && ann.Attributes[restAnnotation.ParamProtected] != "true"
+	ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService)
+	return ok && !ann.GetBool(restAnnotation.ParamProtected)
 }
 
 func GetRestServicePath(s model.Struct) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService); ok {
 		return ann.Attributes[restAnnotation.ParamPath]
 	}
 	return ""
 }
 
 func GetExtractRequestContextMethod(s model.Struct) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService); ok {
 		switch ann.Attributes[restAnnotation.ParamCredentials] {
 		case "all":
 			return "request.NewContext"
@@ -226,17 +289,15 @@ func GetExtractRequestContextMethod(s model.Struct) string {
 }
 
 func IsRestServiceNoValidation(s model.Struct) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
-		return ann.Attributes[restAnnotation.ParamNoValidation] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService); ok {
+		return ann.GetBool(restAnnotation.ParamNoValidation)
 	}
 	return false
 }
 
 func IsRestServiceNoTest(s model.Struct) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(s.DocLines, restAnnotation.TypeRestService); ok {
-		return ann.Attributes[restAnnotation.ParamNoTest] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(s.Annotations, s.DocLines, restAnnotation.TypeRestService); ok {
+		return ann.GetBool(restAnnotation.ParamNoTest)
 	}
 	return false
 }
@@ -246,6 +307,8 @@ func isImportToBeIgnored(imp string) bool {
 		return true
 	}
 	for _, i := range []string{
+		// the templates import golang.org/x/net/context: its Context is the same type as the one of the standard library
+		"context",
 		"golang.org/x/net/context",
 		"github.com/gorilla/mux",
 	} {
@@ -256,17 +319,35 @@ func isImportToBeIgnored(imp string) bool {
 	return false
 }
 
+// ExtractImports returns the import-specs for the packages of the args of the operations: aliased
+// when the args refer to the package by another name than the package-clause
 func ExtractImports(s model.Struct) []string {
-	importsMap := map[string]string{}
+	return extractImports(s, nil)
+}
+
+// ExtractHandlerImports also returns the import-specs for the packages of the input-args of the constructor,
+// that the handlers accept to create the service
+func ExtractHandlerImports(s model.Struct) []string {
+	constructor := s.Constructor()
+	if constructor == nil {
+		return extractImports(s, nil)
+	}
+	return extractImports(s, constructor.InputArgs)
+}
+
+func extractImports(s model.Struct, extraArgs []model.Field) []string {
+	args := []model.Field{}
 	for _, o := range s.Operations {
-		for _, ia := range o.InputArgs {
-			if isImportToBeIgnored(ia.PackageName) == false {
-				importsMap[ia.PackageName] = ia.PackageName
-			}
-		}
-		for _, oa := range o.OutputArgs {
-			if isImportToBeIgnored(oa.PackageName) == false {
-				importsMap[oa.PackageName] = oa.PackageName
+		args = append(args, o.InputArgs...)
+		args = append(args, o.OutputArgs...)
+	}
+	args = append(args, extraArgs...)
+
+	importsMap := map[string]string{}
+	for _, arg := range args {
+		for packagePath, spec := range generationUtil.GetImportSpecs(arg) {
+			if isImportToBeIgnored(packagePath) == false {
+				importsMap[packagePath] = spec
 			}
 		}
 	}
@@ -274,6 +355,7 @@ func ExtractImports(s model.Struct) []string {
 	for _, v := range importsMap {
 		importsList = append(importsList, v)
 	}
+	sort.Strings(importsList)
 
 	return importsList
 }
@@ -288,15 +370,13 @@ func HasOperationsWithInput(s model.Struct) bool {
 }
 
 func IsRestOperation(o model.Operation) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	_, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation)
+	_, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation)
 	return ok
 }
 
 func IsRestOperationNoWrap(o model.Operation) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		return ann.Attributes[restAnnotation.ParamNoWrap] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return ann.GetBool(restAnnotation.ParamNoWrap)
 	}
 	return false
 }
@@ -306,16 +386,14 @@ func IsRestOperationGenerated(o model.Operation) bool {
 }
 
 func HasRestOperationAfter(o model.Operation) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		return ann.Attributes[restAnnotation.ParamAfter] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return ann.GetBool(restAnnotation.ParamAfter)
 	}
 	return false
 }
 
 func GetRestOperationPath(o model.Operation) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
 		return ann.Attributes[restAnnotation.ParamPath]
 	}
 	return ""
@@ -336,24 +414,21 @@ func getAllPathParams(o model.Operation) []string {
 }
 
 func GetRestOperationMethod(o model.Operation) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
 		return ann.Attributes[restAnnotation.ParamMethod]
 	}
 	return ""
 }
 
 func IsRestOperationForm(o model.Operation) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		return ann.Attributes[restAnnotation.ParamForm] == "true"
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		return ann.GetBool(restAnnotation.ParamForm)
 	}
 	return false
 }
 
 func GetRestOperationFormat(o model.Operation) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
 		return ann.Attributes[restAnnotation.ParamFormat]
 	}
 	return ""
@@ -409,8 +484,7 @@ func GetContentType(operation model.Operation) string {
 }
 
 func GetRestOperationFilename(o model.Operation) string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
 		return ann.Attributes[restAnnotation.ParamFilename]
 	}
 	return ""
@@ -425,13 +499,8 @@ func GetRestOperationRolesString(o model.Operation) string {
 }
 
 func GetRestOperationRoles(o model.Operation) []string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		if rolesAttr, ok := ann.Attributes[restAnnotation.ParamRoles]; ok {
-			roles := strings.Split(rolesAttr, ",")
-			for i, r := range roles {
-				roles[i] = strings.Trim(r, " ")
-			}
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		if roles, ok := ann.GetStrings(restAnnotation.ParamRoles); ok {
 			return roles
 		}
 	}
@@ -443,16 +512,8 @@ func GetRestOperationProducesEvents(o model.Operation) string {
 }
 
 func GetRestOperationProducesEventsAsSlice(o model.Operation) []string {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	if ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation); ok {
-		if attrs, ok := ann.Attributes[restAnnotation.ParamProducesEvents]; ok {
-			eventsProduced := []string{}
-			for _, e := range strings.Split(attrs, ",") {
-				evt := strings.TrimSpace(e)
-				if evt != "" {
-					eventsProduced = append(eventsProduced, evt)
-				}
-			}
+	if ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation); ok {
+		if eventsProduced, ok := ann.GetStrings(restAnnotation.ParamProducesEvents); ok {
 			return eventsProduced
 		}
 	}
@@ -689,17 +750,16 @@ func RequiresParamValidation(o model.Operation) bool {
 }
 
 func IsInputArgMandatory(o model.Operation, arg model.Field) bool {
-	annotations := annotation.NewRegistry(restAnnotation.Get())
-	ann, ok := annotations.ResolveAnnotationByName(o.DocLines, restAnnotation.TypeRestOperation)
+	ann, ok := annotationRegistry.FindAnnotation(o.Annotations, o.DocLines, restAnnotation.TypeRestOperation)
 	if !ok {
 		return false
 	}
-	optionalArgsString, ok := ann.Attributes[restAnnotation.ParamOptional]
+	optionalArgs, ok := ann.GetStrings(restAnnotation.ParamOptional)
 	if !ok {
 		return true
 	}
 
-	return !findArgInArray(strings.Split(optionalArgsString, ","), arg.Name)
+	return !findArgInArray(optionalArgs, arg.Name)
 }
 
 func HasUpload(o model.Operation) bool {
@@ -720,7 +780,7 @@ func IsUploadArg(f model.Field) bool {
 }
 
 func IsContextArg(f model.Field) bool {
-	return f.TypeName == "context.Context"
+	return f.TypeName == "context.Context" || isResolvedArg(f, "Context", "context", "golang.org/x/net/context")
 }
 
 func IsRequestContextArg(f model.Field) bool {
@@ -736,19 +796,53 @@ func IsPrimitiveArg(f model.Field) bool {
 }
 
 func IsBoolArg(f model.Field) bool {
-	return f.TypeName == "bool" && !f.IsSlice
+	return isBasicArg(f, "bool") && !f.IsSlice
 }
 
 func IsNumberArg(f model.Field) bool {
-	return f.TypeName == "int" && !f.IsSlice
+	return isBasicArg(f, "int") && !f.IsSlice
 }
 
 func IsStringArg(f model.Field) bool {
-	return f.TypeName == "string" && !f.IsSlice
+	return isBasicArg(f, "string") && !f.IsSlice
 }
 
 func IsStringSliceArg(f model.Field) bool {
-	return f.TypeName == "string" && f.IsSlice
+	return isBasicArg(f, "string") && f.IsSlice
+}
+
+// isBasicArg uses type-information when available: this also recognizes aliases like 'type Name = string'
+func isBasicArg(f model.Field, basicName string) bool {
+	elem := getElemType(f)
+	if elem != nil && elem.Underlying != "" {
+		return elem.Kind == model.KindNamed && elem.PackagePath == "" && elem.Underlying == basicName
+	}
+	return f.TypeName == basicName
+}
+
+// isResolvedArg uses type-information when available: this also recognizes renamed imports
+func isResolvedArg(f model.Field, name string, packagePaths ...string) bool {
+	elem := getElemType(f)
+	if elem == nil || elem.Kind != model.KindNamed || elem.Name != name {
+		return false
+	}
+	for _, packagePath := range packagePaths {
+		if elem.PackagePath == packagePath {
+			return true
+		}
+	}
+	return false
+}
+
+func getElemType(f model.Field) *model.Type {
+	elem := f.Type
+	if elem != nil && f.IsSlice && elem.Kind == model.KindSlice {
+		elem = elem.Elem
+	}
+	if elem != nil && f.IsPointer && elem.Kind == model.KindPointer {
+		elem = elem.Elem
+	}
+	return elem
 }
 
 func ToFirstUpper(in string) string {
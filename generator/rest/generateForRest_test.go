@@ -8,6 +8,7 @@ import (
 
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/MarcGrol/golangAnnotations/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -95,6 +96,158 @@ func TestGenerateForWeb(t *testing.T) {
 
 }
 
+func TestGenerateForGenericRestService(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Position:    model.Position{Filename: "testData/service.go", Line: 12, Column: 6},
+			Name:        "MyService",
+			TypeParams:  []model.TypeParam{{Name: "T", Constraint: "any"}},
+		},
+	}
+
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	assert.Error(t, err)
+	assert.Equal(t, "testData/service.go:12:6: Rest-service MyService is generic: generic receivers are not supported", err.Error())
+
+	_, err = os.Stat(filegen.Prefixed("./testData/httpMyService.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateForUnsupportedParams(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	for _, arg := range []model.Field{
+		{Name: "opts", TypeName: "string", IsSlice: true, IsVariadic: true, Position: model.Position{Filename: "testData/service.go", Line: 20, Column: 40},
+			Type: &model.Type{Kind: model.KindSlice, Elem: &model.Type{Kind: model.KindNamed, Name: "string"}}},
+		{Name: "cb", TypeName: "func() error", Position: model.Position{Filename: "testData/service.go", Line: 20, Column: 40},
+			Type: &model.Type{Kind: model.KindFunc, Results: []model.Field{{TypeName: "error"}}}},
+		{Name: "events", TypeName: "Event", Position: model.Position{Filename: "testData/service.go", Line: 20, Column: 40},
+			Type: &model.Type{Kind: model.KindChan, Elem: &model.Type{Kind: model.KindNamed, Name: "Event"}}},
+		{Name: "w", TypeName: "io.Writer", Position: model.Position{Filename: "testData/service.go", Line: 20, Column: 40},
+			Type: &model.Type{Kind: model.KindNamed, Name: "Writer", Package: "io", PackagePath: "io", Underlying: "interface"}},
+	} {
+		s := []model.Struct{
+			{
+				DocLines:    []string{"// @RestService( path = \"/api\")"},
+				PackageName: "testData",
+				Name:        "MyService",
+				Operations: []*model.Operation{
+					{
+						DocLines:      []string{"// @RestOperation(path = \"/person\", method = \"GET\")"},
+						Name:          "doit",
+						RelatedStruct: &model.Field{TypeName: "MyService"},
+						InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}, arg},
+						OutputArgs:    []model.Field{{TypeName: "error"}},
+					},
+				},
+			},
+		}
+
+		err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "testData/service.go:20:40: Rest-operation MyService.doit: ")
+		assert.Contains(t, err.Error(), fmt.Sprintf("parameter '%s %s' is not supported", arg.Name, arg.TypeString()))
+
+		_, err = os.Stat(filegen.Prefixed("./testData/httpMyService.go"))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestValidateImportedParamsWithoutTypeInformation(t *testing.T) {
+	parsedSources, err := parser.ParseSources(map[string][]byte{"testData/service.go": []byte(`package testData
+
+import (
+	"context"
+
+	"github.com/MarcGrol/golangAnnotations/examples/structExample"
+)
+
+// @RestService( path = "/api" )
+type MyService struct{}
+
+// @RestOperation( method = "POST", path = "/tour" )
+func (s *MyService) createTour(c context.Context, t structExample.TourCreated) error {
+	return nil
+}
+
+// @RestOperation( method = "POST", path = "/callback" )
+func (s *MyService) callback(c context.Context, f func()) error {
+	return nil
+}
+`)})
+	assert.NoError(t, err)
+
+	// without type-information an imported type is accepted: only what is clearly not a value is rejected
+	service := parsedSources.Structs[0]
+	assert.Len(t, service.Operations, 2)
+	createTour := service
+	createTour.Operations = service.Operations[:1]
+	assert.NoError(t, validateRestOperations(createTour))
+
+	err = validateRestOperations(service)
+	assert.Error(t, err)
+	assert.Equal(t, "testData/service.go:18:49: Rest-operation MyService.callback: func-typed parameter 'f func()' is not supported", err.Error())
+}
+
+func TestGenerateForValueReceiver(t *testing.T) {
+	cleanup()
+	defer cleanup()
+
+	s := []model.Struct{
+		{
+			DocLines:    []string{"// @RestService( path = \"/api\")"},
+			PackageName: "testData",
+			Name:        "MyService",
+			Fields:      []model.Field{{Name: "calls", TypeName: "int"}},
+			Operations: []*model.Operation{
+				{
+					DocLines:      []string{"// @RestOperation(path = \"/person\", method = \"GET\")"},
+					Position:      model.Position{Filename: "testData/service.go", Line: 12, Column: 20},
+					Name:          "doit",
+					RelatedStruct: &model.Field{Name: "s", TypeName: "MyService"},
+					ReceiverKind:  model.ReceiverValue,
+					InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+					OutputArgs:    []model.Field{{TypeName: "error"}},
+				},
+			},
+		},
+	}
+
+	err := NewGenerator().Generate("testData", model.ParsedSources{Structs: s})
+	assert.Error(t, err)
+	assert.Equal(t, "testData/service.go:12:20: Rest-operation MyService.doit has a value-receiver: use a pointer-receiver so it does not operate on a copy of the service", err.Error())
+
+	_, err = os.Stat(filegen.Prefixed("./testData/httpMyService.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateForValueReceiverWithoutFields(t *testing.T) {
+	s := model.Struct{
+		DocLines:    []string{"// @RestService( path = \"/api\")"},
+		PackageName: "testData",
+		Name:        "MyService",
+		Operations: []*model.Operation{
+			{
+				DocLines:      []string{"// @RestOperation(path = \"/person\", method = \"GET\")"},
+				Name:          "doit",
+				RelatedStruct: &model.Field{Name: "s", TypeName: "MyService"},
+				ReceiverKind:  model.ReceiverValue,
+				InputArgs:     []model.Field{{Name: "c", TypeName: "context.Context"}},
+				OutputArgs:    []model.Field{{TypeName: "error"}},
+			},
+		},
+	}
+
+	// a service without fields has no state to copy
+	assert.NoError(t, validateRestOperations(s))
+}
+
 func TestIsRestService(t *testing.T) {
 	s := model.Struct{
 		DocLines: []string{
@@ -178,6 +331,65 @@ func TestIsPrimitiveFalse(t *testing.T) {
 	assert.False(t, IsPrimitiveArg(f))
 }
 
+func TestIsPrimitiveTypeChecked(t *testing.T) {
+	alias := model.Field{Name: "name", TypeName: "Name", Type: &model.Type{Kind: model.KindNamed, Name: "Name", Underlying: "string"}}
+	assert.True(t, IsStringArg(alias))
+
+	defined := model.Field{Name: "meta", TypeName: "Metadata", Type: &model.Type{Kind: model.KindNamed, Name: "Metadata", PackagePath: "a/b", Underlying: "string"}}
+	assert.False(t, IsPrimitiveArg(defined))
+}
+
+func TestIsContextArgRenamedImport(t *testing.T) {
+	f := model.Field{Name: "c", TypeName: "stdcontext.Context", Type: &model.Type{Kind: model.KindNamed, Name: "Context", Package: "stdcontext", PackagePath: "context", Underlying: "interface"}}
+	assert.True(t, IsContextArg(f))
+}
+
+func TestExtractImports(t *testing.T) {
+	s := model.Struct{
+		Operations: []*model.Operation{
+			{
+				InputArgs: []model.Field{
+					{Name: "c", TypeName: "context.Context", PackageName: "golang.org/x/net/context"},
+					{Name: "stdc", TypeName: "context.Context", PackageName: "context"},
+					{Name: "id", TypeName: "uuid.UUID", PackageName: "github.com/gofrs/uuid/v5"},
+					{Name: "amount", TypeName: "Amount", PackageName: "github.com/shop/money"},
+				},
+				OutputArgs: []model.Field{
+					{TypeName: "api.Order", IsSlice: true, IsPointer: true, PackageName: "github.com/shop/model"},
+					{TypeName: "error"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{
+		`"github.com/gofrs/uuid/v5"`,
+		`. "github.com/shop/money"`,
+		`api "github.com/shop/model"`,
+	}, ExtractImports(s))
+}
+
+func TestExtractHandlerImports(t *testing.T) {
+	s := model.Struct{
+		Operations: []*model.Operation{
+			{
+				InputArgs: []model.Field{
+					{Name: "c", TypeName: "context.Context", PackageName: "golang.org/x/net/context"},
+					{Name: "id", TypeName: "uuid.UUID", PackageName: "github.com/gofrs/uuid/v5"},
+				},
+			},
+		},
+		Constructors: []*model.Operation{
+			{
+				Name:       "NewService",
+				InputArgs:  []model.Field{{Name: "db", TypeName: "sql.DB", IsPointer: true, PackageName: "database/sql"}},
+				OutputArgs: []model.Field{{TypeName: "Service", IsPointer: true}},
+			},
+		},
+	}
+	assert.Equal(t, []string{`"github.com/gofrs/uuid/v5"`}, ExtractImports(s))
+	assert.Equal(t, []string{`"database/sql"`, `"github.com/gofrs/uuid/v5"`}, ExtractHandlerImports(s))
+}
+
 func TestIsNumberTrue(t *testing.T) {
 	f := model.Field{Name: "uid", TypeName: "int"}
 	assert.True(t, IsNumberArg(f))
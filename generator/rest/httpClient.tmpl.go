@@ -6,14 +6,17 @@ const httpClientTemplate = `// +build !appengine
 
 package {{.PackageName}}
 
-import (
+{{define "logger"}}mylog.New(){{end -}}
+
+{{block "imports" .}}import (
     "encoding/json"
     "net/http"
     "net/http/httputil"
     "strings"
     "time"
-    "golang.org/x/net/context"
-)
+    "golang.org/x/net/context"{{range ExtractImports .}}
+    {{.}}{{end}}
+){{end}}
 
 {{ $serviceName := .Name }}
 
@@ -64,7 +67,7 @@ func (c *HTTPClient) {{ToFirstUpper .Name}}(ctx context.Context, url string {{if
     if debug {
         dump, err := httputil.DumpRequest(req, true)
         if err == nil {
-            mylog.New().Debug(ctx, "HTTP request-payload:\n %s", dump)
+            {{template "logger"}}.Debug(ctx, "HTTP request-payload:\n %s", dump)
         }
     }
 
@@ -83,7 +86,7 @@ func (c *HTTPClient) {{ToFirstUpper .Name}}(ctx context.Context, url string {{if
     if debug {
         respDump, err := httputil.DumpResponse(res, true)
         if err == nil {
-            mylog.New().Debug(ctx,"HTTP response-payload:\n%s", string(respDump))
+            {{template "logger"}}.Debug(ctx,"HTTP response-payload:\n%s", string(respDump))
         }
     }
 
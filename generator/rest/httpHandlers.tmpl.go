@@ -4,10 +4,14 @@ const httpHandlersTemplate = `// Generated automatically by golangAnnotations: d
 
 package {{.PackageName}}
 
-import (
+{{define "logger"}}mylog.New(){{end -}}
+{{define "create-context"}}ctx.New.CreateContext(r){{end -}}
+
+{{block "imports" .}}import (
 	"github.com/gorilla/mux"
-	"golang.org/x/net/context"
-)
+	"golang.org/x/net/context"{{range ExtractHandlerImports .}}
+	{{.}}{{end}}
+){{end}}
 
 {{ $service := . }}
 
@@ -35,6 +39,22 @@ func (ts *{{.Name}}) HTTPHandlerWithRouter(router *mux.Router) *mux.Router {
     return router
 }
 
+{{with .Constructor -}}
+// New{{ToFirstUpper $service.Name}}HTTPHandler creates the service with {{.Name}} and registers its endpoints in a new router
+func New{{ToFirstUpper $service.Name}}HTTPHandler({{GetConstructorParams .}}) {{if .ReturnsError}}(http.Handler, error){{else}}http.Handler{{end}} {
+    {{if .ReturnsError -}}
+    service, err := {{.Name}}({{GetConstructorArgs .}})
+    if err != nil {
+        return nil, err
+    }
+    return service.HTTPHandler(), nil
+    {{- else -}}
+    service := {{.Name}}({{GetConstructorArgs .}})
+    return service.HTTPHandler()
+    {{- end}}
+}
+{{end -}}
+
 {{ $extractRequestContextMethod := GetExtractRequestContextMethod . }}
 {{ $noValidation := IsRestServiceNoValidation . }}
 
@@ -48,7 +68,7 @@ func {{$oper.Name}}( service *{{$service.Name}} ) http.HandlerFunc {
         var err error
 
         {{if NeedsContext $oper -}}
-			{{GetContextName $oper}} := ctx.New.CreateContext(r)
+			{{GetContextName $oper}} := {{template "create-context"}}
 			preLogicHook( c, w, r )
         {{else -}}
 			preLogicHook( nil, w, r )
@@ -211,19 +231,19 @@ func {{$oper.Name}}( service *{{$service.Name}} ) http.HandlerFunc {
             {{if HasOutput . -}}
 				err = json.NewEncoder(w).Encode(result)
 				if err != nil {
-					mylog.New().Warning(c, "Error writing json-response: %s", err)
+					{{template "logger"}}.Warning(c, "Error writing json-response: %s", err)
 				}
 			{{end -}}
 		{{else if IsRestOperationHTML . -}}
 			{{if HasOutput . -}}
 				err = service.{{$oper.Name}}WriteHTML(w, result)
 				if err != nil {
-					mylog.New().Warning(c, "Error writing html-response: %s", err)
+					{{template "logger"}}.Warning(c, "Error writing html-response: %s", err)
 				}
 			{{else -}}
 				err = service.{{$oper.Name}}WriteHTML(w)
 				if err != nil {
-					mylog.New().Warning(c, "Error writing html-response: %s", err)
+					{{template "logger"}}.Warning(c, "Error writing html-response: %s", err)
 				}
 			{{end -}}
 		{{else if IsRestOperationCSV . -}}
@@ -252,7 +272,7 @@ func {{$oper.Name}}( service *{{$service.Name}} ) http.HandlerFunc {
 func {{$oper.Name}}( service *{{$service.Name}} ) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         {{if NeedsContext $oper -}}
-			{{GetContextName $oper}} := ctx.New.CreateContext(r)
+			{{GetContextName $oper}} := {{template "create-context"}}
 		{{end -}}
         service.{{$oper.Name}}({{GetInputParamString . }})
     }
@@ -1,6 +1,8 @@
 package restAnnotation
 
-import "github.com/MarcGrol/golangAnnotations/annotation"
+import (
+	"github.com/MarcGrol/golangAnnotations/annotation"
+)
 
 const (
 	TypeRestOperation   = "RestOperation"
@@ -25,14 +27,58 @@ const (
 func Get() []annotation.AnnotationDescriptor {
 	return []annotation.AnnotationDescriptor{
 		{
-			Name:       TypeRestService,
-			ParamNames: []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath},
-			Validator:  validateRestServiceAnnotation,
+			Name:        TypeRestService,
+			Description: "Generates http-handlers, a client and test-helpers for the rest-operations of the struct",
+			ParamNames:  []string{ParamCredentials, ParamNoValidation, ParamProtected, ParamNoTest, ParamPath},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamNoValidation: annotation.ParamTypeBool,
+				ParamProtected:    annotation.ParamTypeBool,
+				ParamNoTest:       annotation.ParamTypeBool,
+			},
+			ParamDescriptions: map[string]string{
+				ParamCredentials:  "Credentials to extract the request-context with: all, admin or none",
+				ParamNoValidation: "Skip the validation of the input of all operations",
+				ParamProtected:    "Require an authenticated request for all operations",
+				ParamNoTest:       "Do not generate test-helpers",
+				ParamPath:         "Path-prefix of all operations",
+			},
+			Required:  []string{ParamPath},
+			Unique:    true,
+			Targets:   []annotation.Target{annotation.TargetStruct},
+			Validator: validateRestServiceAnnotation,
 		},
 		{
-			Name:       TypeRestOperation,
-			ParamNames: []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents},
-			Validator:  validateRestOperationAnnotation,
+			Name:        TypeRestOperation,
+			Description: "Exposes the method as http-endpoint of the rest-service",
+			ParamNames:  []string{ParamNoWrap, ParamAfter, ParamPath, ParamMethod, ParamTransactional, ParamForm, ParamFormat, ParamFilename, ParamOptional, ParamRoles, ParamProducesEvents},
+			ParamTypes: map[string]annotation.ParamType{
+				ParamNoWrap:         annotation.ParamTypeBool,
+				ParamAfter:          annotation.ParamTypeBool,
+				ParamTransactional:  annotation.ParamTypeBool,
+				ParamForm:           annotation.ParamTypeBool,
+				ParamOptional:       annotation.ParamTypeList,
+				ParamRoles:          annotation.ParamTypeList,
+				ParamProducesEvents: annotation.ParamTypeList,
+			},
+			ParamDescriptions: map[string]string{
+				ParamNoWrap:         "Do not generate the http-handler: the service provides it",
+				ParamAfter:          "Call <Operation>HandleAfter of the service after the operation",
+				ParamPath:           "Path of the endpoint, with path-parameters like {uid}",
+				ParamMethod:         "Http-method, like GET or POST",
+				ParamTransactional:  "Run the operation in a transaction",
+				ParamForm:           "Read the input from a form instead of the body",
+				ParamFormat:         "Format of the response: JSON, HTML, CSV, TXT, MD, no_content or custom",
+				ParamFilename:       "Download the response as attachment with this filename",
+				ParamOptional:       "Input-arguments that are not mandatory",
+				ParamRoles:          "Roles that are allowed to call the endpoint",
+				ParamProducesEvents: "Events produced by the operation, like Aggregate.Event",
+			},
+			Required:          []string{ParamMethod},
+			Unique:            true,
+			ExclusiveWith:     []string{"EventOperation"}, // of the event-service generator: by name, so that the generators stay independent
+			RequiresEnclosing: []string{TypeRestService},
+			Targets:           []annotation.Target{annotation.TargetOperation},
+			Validator:         validateRestOperationAnnotation,
 		}}
 }
 
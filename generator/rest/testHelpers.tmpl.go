@@ -6,7 +6,7 @@ const testHelpersTemplate = `// +build !appengine
 
 package {{.PackageName}}
 
-import "golang.org/x/net/context"
+{{block "imports" .}}import "golang.org/x/net/context"{{end}}
 
 var (
     setCookieHook = func(r *http.Request, headers map[string]string) {}
@@ -2,56 +2,183 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
-	"github.com/MarcGrol/golangAnnotations/generator/event"
-	"github.com/MarcGrol/golangAnnotations/generator/eventService"
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/config"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
+	"github.com/MarcGrol/golangAnnotations/generator"
 	"github.com/MarcGrol/golangAnnotations/generator/filegen"
 	"github.com/MarcGrol/golangAnnotations/generator/generationUtil"
-	"github.com/MarcGrol/golangAnnotations/generator/jsonHelpers"
-	"github.com/MarcGrol/golangAnnotations/generator/repository"
-	"github.com/MarcGrol/golangAnnotations/generator/rest"
-	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/MarcGrol/golangAnnotations/parser"
 )
 
 const (
-	version = "0.7"
+	version = "0.8"
 )
 
-var inputDir *string
+type dirList []string
+
+func (l *dirList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *dirList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+var inputDirs dirList
+var typeCheck *bool
+var buildTags *string
+var cacheDir *string
+var stdin *bool
+var stdinFilename *string
+var strictAnnotations *bool
+var configFilename *string
+var templateDir *string
+var listAnnotations *bool
+var format *string
+var toolIdentity string
 
 func main() {
 	processArgs()
 
-	parsedSources, err := parser.New().ParseSourceDir(*inputDir, "^.*.go$", filegen.ExcludeMatchPattern())
+	err := generator.Configure(*configFilename, *templateDir)
 	if err != nil {
-		log.Printf("Error parsing golang sources in %s:%s", *inputDir, err)
+		log.Printf("Error configuring generators:%s", err)
 		os.Exit(1)
 	}
 
-	marshalled, err := json.MarshalIndent(parsedSources, "", "\t")
-	if err != nil {
-		panic(err)
+	if *listAnnotations {
+		err := processListAnnotations(*format)
+		if err != nil {
+			log.Printf("Error documenting annotations:%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *stdin {
+		err := processStdin()
+		if err != nil {
+			log.Printf("Error parsing golang source from stdin:%s", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	}
-	targetFilename := filegen.Prefixed(*inputDir + "/" + "ast.json")
-	err = ioutil.WriteFile(targetFilename, marshalled, 0644)
+
+	dirs, err := parser.FindSourceDirs(inputDirs, "^.*.go$", filegen.ExcludeMatchPattern())
 	if err != nil {
-		panic(err)
+		log.Printf("Error finding golang sources in %s:%s", inputDirs.String(), err)
+		os.Exit(1)
+	}
+
+	if *cacheDir != "" {
+		// once for all packages: it hashes the whole executable
+		toolIdentity = generator.ToolIdentity(version)
 	}
 
-	runAllGenerators(*inputDir, parsedSources)
+	failures := map[string]error{}
+	for _, dir := range dirs {
+		err := processDir(dir)
+		if err != nil {
+			failures[dir] = err
+		}
+	}
 
+	printReport(dirs, failures)
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
 	os.Exit(0)
 }
 
+func processDir(inputDir string) error {
+	return generator.Run(inputDir, generator.Options{
+		TypeCheck:         *typeCheck,
+		BuildTags:         parseBuildTags(*buildTags),
+		CacheDir:          *cacheDir,
+		ToolIdentity:      toolIdentity,
+		StrictAnnotations: *strictAnnotations,
+	})
+}
+
+// processStdin prints the model of a single source-file read from stdin, without generating code
+func processStdin() error {
+	src, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	parsedSources, err := parser.ParseSources(map[string][]byte{*stdinFilename: src}, parseBuildTags(*buildTags)...)
+	if err != nil {
+		return err
+	}
+	err = generator.ValidateAnnotations(parsedSources, *strictAnnotations)
+	if err != nil {
+		return err
+	}
+	marshalled, err := json.MarshalIndent(parsedSources, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(os.Stdout, "%s\n", marshalled)
+	return err
+}
+
+// processListAnnotations prints the catalogue of the annotations of all generators
+func processListAnnotations(format string) error {
+	entries := []annotation.CatalogueEntry{}
+	for _, name := range generationUtil.GetGeneratorNames() {
+		g, _ := generationUtil.GetGenerator(name)
+		for _, descriptor := range g.GetAnnotations() {
+			entries = append(entries, annotation.CatalogueEntry{Generator: name, Descriptor: descriptor})
+		}
+	}
+	return annotation.WriteCatalogue(os.Stdout, format, entries)
+}
+
+func parseBuildTags(value string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func printReport(dirs []string, failures map[string]error) {
+	if len(dirs) <= 1 && len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: Processed %d package(s), %d failed\n", "golangAnnotations", len(dirs), len(failures))
+	for _, dir := range dirs {
+		if err, failed := failures[dir]; failed {
+			var diagnostic diagnostics.Diagnostic
+			if errors.As(err, &diagnostic) {
+				// on a line of its own, so that editors and CI can jump to the position: not repeated after the package
+				diagnostics.Report(diagnostic)
+				fmt.Fprintf(os.Stderr, "%s: Failed package '%s'\n", "golangAnnotations", dir)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s: Failed package '%s': %s\n", "golangAnnotations", dir, err)
+		}
+	}
+}
+
 func printUsage() {
 	fmt.Fprintf(os.Stderr, "\nUsage:\n")
-	fmt.Fprintf(os.Stderr, " %s [flags]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s [flags] [dir ...]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s [flags] -stdin < file.go\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s [-config file] -list-annotations [-format text|markdown|json-schema]\n", os.Args[0])
 	flag.PrintDefaults()
 	fmt.Fprintf(os.Stderr, "\n")
 	os.Exit(1)
@@ -63,7 +190,17 @@ func printVersion() {
 }
 
 func processArgs() {
-	inputDir = flag.String("input-dir", "", "Directory to be examined")
+	flag.Var(&inputDirs, "input-dir", "Directory to be examined: can be repeated, use dir/... to include all packages below dir")
+	typeCheck = flag.Bool("type-check", false, "Resolve types using the type-checker (slower, but exact): cannot be combined with -cache-dir")
+	buildTags = flag.String("tags", "", "Comma-separated list of build-tags to consider satisfied, like 'go build -tags'")
+	cacheDir = flag.String("cache-dir", "", "Directory to cache parsed sources in: caching is disabled by default and cannot be combined with -type-check")
+	stdin = flag.Bool("stdin", false, "Parse a single source-file from stdin and print its model as json to stdout, without generating code")
+	stdinFilename = flag.String("stdin-filename", "stdin.go", "Filename of the source read with -stdin, as it appears in the model")
+	strictAnnotations = flag.Bool("strict-annotations", false, "Fail on invalid annotations instead of warning: unknown names and parameters, missing parameters and invalid values")
+	configFilename = flag.String("config", "", "Project-config declaring plugins and template-dir: default "+config.Filename+" in the working directory or its parents, up to the root of the module or repository")
+	templateDir = flag.String("template-dir", "", "Directory with overrides of the templates of the generators, like http-handlers.tmpl: takes precedence over the template-dir of the project-config")
+	listAnnotations = flag.Bool("list-annotations", false, "Print the annotations of all generators with their parameters, without generating code")
+	format = flag.String("format", annotation.CatalogueText, "Format of -list-annotations: text, markdown or json-schema")
 	help := flag.Bool("help", false, "Usage information")
 	version := flag.Bool("version", false, "Version information")
 
@@ -75,23 +212,12 @@ func processArgs() {
 	if version != nil && *version == true {
 		printVersion()
 	}
-	if inputDir == nil || *inputDir == "" {
+	inputDirs = append(inputDirs, flag.Args()...)
+	if len(inputDirs) == 0 && !*stdin && !*listAnnotations {
 		printUsage()
 	}
-}
-
-func runAllGenerators(inputDir string, parsedSources model.ParsedSources) error {
-	for name, g := range map[string]generationUtil.Generator{
-		"event":         event.NewGenerator(),
-		"event-service": eventService.NewGenerator(),
-		"json-helpers":  jsonHelpers.NewGenerator(),
-		"rest":          rest.NewGenerator(),
-		"repository":    repository.NewGenerator(),
-	} {
-		err := g.Generate(inputDir, parsedSources)
-		if err != nil {
-			return fmt.Errorf("Error generating module %s: %s", name, err)
-		}
+	if *typeCheck && *cacheDir != "" {
+		log.Printf("Flags -type-check and -cache-dir cannot be combined: the type-checked parser does not use the cache")
+		os.Exit(1)
 	}
-	return nil
 }
@@ -0,0 +1,205 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetAnnotation returns the annotation of the field with the given name
+func (f Field) GetAnnotation(name string) (Annotation, bool) {
+	for _, annotation := range f.Annotations {
+		if annotation.Name == name {
+			return annotation, true
+		}
+	}
+	return Annotation{}, false
+}
+
+// HasAnnotation reports whether the field is annotated with the given name
+func (f Field) HasAnnotation(name string) bool {
+	_, ok := f.GetAnnotation(name)
+	return ok
+}
+
+// String returns the textual form of the value: the elements of a list are separated by commas
+func (v AnnotationValue) String() string {
+	switch v.Kind {
+	case AnnotationValueList:
+		elements := []string{}
+		for _, element := range v.List {
+			elements = append(elements, element.String())
+		}
+		return strings.Join(elements, ",")
+	case AnnotationValueAnnotation:
+		if v.Annotation == nil {
+			return ""
+		}
+		return v.Annotation.String()
+	default:
+		return v.Text
+	}
+}
+
+// Bool converts the value to a bool: a string is accepted when it holds a bool-literal
+func (v AnnotationValue) Bool() (bool, bool) {
+	if v.Kind != AnnotationValueBool && v.Kind != AnnotationValueString {
+		return false, false
+	}
+	b, err := strconv.ParseBool(v.Text)
+	if err != nil {
+		return false, false
+	}
+	return b, true
+}
+
+// Int converts the value to an int: a string is accepted when it holds an integer-literal
+func (v AnnotationValue) Int() (int, bool) {
+	if v.Kind != AnnotationValueInt && v.Kind != AnnotationValueString {
+		return 0, false
+	}
+	i, err := strconv.ParseInt(v.Text, 0, 0)
+	if err != nil {
+		return 0, false
+	}
+	return int(i), true
+}
+
+// Float converts the value to a float64: integers and strings holding a number are accepted
+func (v AnnotationValue) Float() (float64, bool) {
+	if v.Kind != AnnotationValueFloat && v.Kind != AnnotationValueInt && v.Kind != AnnotationValueString {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v.Text, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// Strings converts the value to a slice of strings: a string is split on commas, example: "a, b"
+func (v AnnotationValue) Strings() []string {
+	strs := []string{}
+	switch v.Kind {
+	case AnnotationValueList:
+		for _, element := range v.List {
+			strs = append(strs, element.String())
+		}
+	case AnnotationValueAnnotation:
+		strs = append(strs, v.String())
+	default:
+		for _, s := range strings.Split(v.Text, ",") {
+			s = strings.TrimSpace(s)
+			if s != "" {
+				strs = append(strs, s)
+			}
+		}
+	}
+	return strs
+}
+
+// String returns the annotation as it could be written in the sources, example: @X(a = "A")
+func (a Annotation) String() string {
+	names := []string{}
+	for name := range a.Attributes {
+		names = append(names, name)
+	}
+	for name := range a.Values {
+		if _, ok := a.Attributes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	attrs := []string{}
+	for _, name := range names {
+		v, _ := a.value(name)
+		attrs = append(attrs, fmt.Sprintf("%s = %s", name, v.literal()))
+	}
+	return fmt.Sprintf("@%s(%s)", a.Name, strings.Join(attrs, ", "))
+}
+
+func (v AnnotationValue) literal() string {
+	switch v.Kind {
+	case AnnotationValueString:
+		return strconv.Quote(v.Text)
+	case AnnotationValueList:
+		elements := []string{}
+		for _, element := range v.List {
+			elements = append(elements, element.literal())
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case AnnotationValueAnnotation:
+		return v.String()
+	default:
+		return v.Text
+	}
+}
+
+// value returns the typed value of the attribute: an annotation that was built by hand, without
+// values, provides its attributes as strings
+func (a Annotation) value(name string) (AnnotationValue, bool) {
+	if v, ok := a.Values[name]; ok {
+		return v, true
+	}
+	if text, ok := a.Attributes[name]; ok {
+		return AnnotationValue{Kind: AnnotationValueString, Text: text}, true
+	}
+	return AnnotationValue{}, false
+}
+
+// GetString returns the textual form of the attribute
+func (a Annotation) GetString(name string) (string, bool) {
+	v, ok := a.value(name)
+	if !ok {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// GetBool reports whether the attribute is true
+func (a Annotation) GetBool(name string) bool {
+	v, ok := a.value(name)
+	if !ok {
+		return false
+	}
+	b, _ := v.Bool()
+	return b
+}
+
+// GetInt returns the attribute as int
+func (a Annotation) GetInt(name string) (int, bool) {
+	v, ok := a.value(name)
+	if !ok {
+		return 0, false
+	}
+	return v.Int()
+}
+
+// GetFloat returns the attribute as float64
+func (a Annotation) GetFloat(name string) (float64, bool) {
+	v, ok := a.value(name)
+	if !ok {
+		return 0, false
+	}
+	return v.Float()
+}
+
+// GetStrings returns the attribute as slice of strings
+func (a Annotation) GetStrings(name string) ([]string, bool) {
+	v, ok := a.value(name)
+	if !ok {
+		return nil, false
+	}
+	return v.Strings(), true
+}
+
+// GetNested returns the annotation that is the value of the attribute, example: @X(y = @Y(a = "A"))
+func (a Annotation) GetNested(name string) (Annotation, bool) {
+	v, ok := a.value(name)
+	if !ok || v.Kind != AnnotationValueAnnotation || v.Annotation == nil {
+		return Annotation{}, false
+	}
+	return *v.Annotation, true
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// AssumedPackageName returns the name of a package as goimports assumes it from its import-path:
+// a major-version suffix like in github.com/gofrs/uuid/v5 or gopkg.in/yaml.v2 is not part of the name
+func AssumedPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") && isDigits(base[1:]) && path.Dir(importPath) != "." {
+		base = path.Base(path.Dir(importPath))
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if idx := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); idx >= 0 {
+		base = base[:idx]
+	}
+	return base
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDot reports whether the package is imported into the file-scope, like import . "pkg"
+func (i Import) IsDot() bool {
+	return i.Name == "."
+}
+
+// IsBlank reports whether the package is imported for its side-effects only, like import _ "pkg"
+func (i Import) IsBlank() bool {
+	return i.Name == "_"
+}
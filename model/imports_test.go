@@ -0,0 +1,15 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssumedPackageName(t *testing.T) {
+	assert.Equal(t, "io", AssumedPackageName("io"))
+	assert.Equal(t, "uuid", AssumedPackageName("github.com/gofrs/uuid/v5"))
+	assert.Equal(t, "yaml", AssumedPackageName("gopkg.in/yaml.v2"))
+	assert.Equal(t, "colorable", AssumedPackageName("github.com/mattn/go-colorable"))
+	assert.Equal(t, "v2", AssumedPackageName("v2"))
+}
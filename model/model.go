@@ -9,74 +9,245 @@ type ParsedSources struct {
 	Interfaces []Interface `json:"interfaces,omitempty"`
 	Typedefs   []Typedef   `json:"typedefs,omitempty"`
 	Enums      []Enum      `json:"enums,omitempty"`
+	Constants  []Constant  `json:"constants,omitempty"`
+	Variables  []Variable  `json:"variables,omitempty"`
+	Imports    []Import    `json:"imports,omitempty"`
+	Packages   []Package   `json:"packages,omitempty"` // files with a package-doc
+}
+
+// @JsonStruct()
+type Package struct {
+	Name         string       `json:"name"`
+	Filename     string       `json:"filename"`
+	Position     Position     `json:"position"`
+	DocLines     []string     `json:"docLines,omitempty"`
+	DocPositions []Position   `json:"docPositions,omitempty"` // positions of the DocLines
+	Annotations  []Annotation `json:"annotations,omitempty"`  // recognized in DocLines: defaults for the annotations of the package
+}
+
+// @JsonStruct()
+type Import struct {
+	Filename  string   `json:"filename"`
+	Position  Position `json:"position"`
+	Name      string   `json:"name,omitempty"` // as written in source: an alias, "." for a dot-import or "_" for a blank import
+	Path      string   `json:"path"`
+	Qualifier string   `json:"qualifier,omitempty"` // name by which the file refers to the package: empty for dot- and blank-imports
 }
 
 // @JsonStruct()
 type Operation struct {
-	PackageName   string   `json:"packageName,omitempty"`
-	Filename      string   `json:"filename,omitempty"`
-	DocLines      []string `json:"docLines,omitempty"`
-	RelatedStruct *Field   `json:"relatedStruct,omitempty"` // optional
-	Name          string   `json:"name"`
-	InputArgs     []Field  `json:"inputArgs,omitempty"`
-	OutputArgs    []Field  `json:"outputArgs,omitempty"`
-	CommentLines  []string `json:"commentLines,omitempty"`
+	PackageName   string       `json:"packageName,omitempty"`
+	Filename      string       `json:"filename,omitempty"`
+	Position      Position     `json:"position"`
+	DocLines      []string     `json:"docLines,omitempty"`
+	DocPositions  []Position   `json:"docPositions,omitempty"`  // positions of the DocLines
+	RelatedStruct *Field       `json:"relatedStruct,omitempty"` // optional
+	ReceiverKind  string       `json:"receiverKind,omitempty"`  // methods only: ReceiverPointer or ReceiverValue
+	Name          string       `json:"name"`
+	IsExported    bool         `json:"isExported,omitempty"`
+	TypeParams    []TypeParam  `json:"typeParams,omitempty"`
+	InputArgs     []Field      `json:"inputArgs,omitempty"`
+	OutputArgs    []Field      `json:"outputArgs,omitempty"`
+	CommentLines  []string     `json:"commentLines,omitempty"`
+	Annotations   []Annotation `json:"annotations,omitempty"` // recognized in DocLines
 }
 
 // @JsonStruct()
 type Struct struct {
 	PackageName  string       `json:"packageName"`
 	Filename     string       `json:"filename"`
+	Position     Position     `json:"position"`
 	DocLines     []string     `json:"docLines,omitempty"`
+	DocPositions []Position   `json:"docPositions,omitempty"` // positions of the DocLines
 	Name         string       `json:"name"`
+	TypeParams   []TypeParam  `json:"typeParams,omitempty"`
 	Fields       []Field      `json:"fields,omitempty"`
 	Operations   []*Operation `json:"operations,omitempty"`
+	Constructors []*Operation `json:"constructors,omitempty"` // free functions NewX returning X or *X, optionally with an error
 	CommentLines []string     `json:"commentLines,omitempty"`
+	Annotations  []Annotation `json:"annotations,omitempty"` // recognized in DocLines
+
+	// Flattened fields and operations promoted from embedded structs, using the go rules for depth and ambiguity
+	PromotedFields     []Field      `json:"promotedFields,omitempty"`
+	PromotedOperations []*Operation `json:"promotedOperations,omitempty"`
 }
 
 // @JsonStruct()
 type Interface struct {
 	PackageName  string      `json:"packageName"`
 	Filename     string      `json:"filename"`
+	Position     Position    `json:"position"`
 	DocLines     []string    `json:"docLines,omitempty"`
 	Name         string      `json:"name"`
+	TypeParams   []TypeParam `json:"typeParams,omitempty"`
 	Methods      []Operation `json:"methods,omitempty"`
 	CommentLines []string    `json:"commentLines,omitempty"`
 }
 
 // @JsonStruct()
 type Field struct {
-	PackageName  string   `json:"packageName,omitempty"`
-	DocLines     []string `json:"docLines,omitempty"`
-	Name         string   `json:"name,omitempty"`
-	TypeName     string   `json:"typeName,omitempty"`
-	IsSlice      bool     `json:"isSlice,omitempty"`
-	IsPointer    bool     `json:"isPointer,omitempty"`
-	Tag          string   `json:"tag,omitempty"`
-	CommentLines []string `json:"commentLines,omitempty"`
+	PackageName      string       `json:"packageName,omitempty"`
+	Position         Position     `json:"position"`
+	DocLines         []string     `json:"docLines,omitempty"`
+	DocPositions     []Position   `json:"docPositions,omitempty"` // positions of the DocLines
+	Name             string       `json:"name,omitempty"`
+	TypeName         string       `json:"typeName,omitempty"`
+	IsSlice          bool         `json:"isSlice,omitempty"`
+	IsPointer        bool         `json:"isPointer,omitempty"`
+	IsTypeParam      bool         `json:"isTypeParam,omitempty"`
+	IsVariadic       bool         `json:"isVariadic,omitempty"` // last parameter of an operation only: the type is the slice it behaves as
+	IsEmbedded       bool         `json:"isEmbedded,omitempty"`
+	PromotedVia      []string     `json:"promotedVia,omitempty"` // promoted fields only: embedded fields it is promoted through, prefixed with '*' when embedded as pointer
+	Type             *Type        `json:"type,omitempty"`
+	Tag              string       `json:"tag,omitempty"`
+	Tags             []Tag        `json:"tags,omitempty"` // decoded Tag: in order of appearance
+	CommentLines     []string     `json:"commentLines,omitempty"`
+	CommentPositions []Position   `json:"commentPositions,omitempty"` // positions of the CommentLines
+	Annotations      []Annotation `json:"annotations,omitempty"`      // recognized in DocLines and CommentLines
+}
+
+// @JsonStruct()
+type Annotation struct {
+	Name       string                     `json:"name"`
+	Attributes map[string]string          `json:"attributes,omitempty"` // textual form of the Values
+	Values     map[string]AnnotationValue `json:"values,omitempty"`
+}
+
+// @JsonStruct()
+type AnnotationValue struct {
+	Kind       string            `json:"kind"`
+	Text       string            `json:"text,omitempty"` // unquoted string or literal of a bool or number
+	List       []AnnotationValue `json:"list,omitempty"`
+	Annotation *Annotation       `json:"annotation,omitempty"`
+}
+
+const (
+	AnnotationValueString     = "string"
+	AnnotationValueBool       = "bool"
+	AnnotationValueInt        = "int"
+	AnnotationValueFloat      = "float"
+	AnnotationValueList       = "list"
+	AnnotationValueAnnotation = "annotation"
+)
+
+const (
+	ReceiverPointer = "pointer"
+	ReceiverValue   = "value"
+)
+
+const (
+	KindNamed     = "named"
+	KindPointer   = "pointer"
+	KindSlice     = "slice"
+	KindArray     = "array"
+	KindMap       = "map"
+	KindChan      = "chan"
+	KindFunc      = "func"
+	KindStruct    = "struct"
+	KindInterface = "interface"
+)
+
+// Type describes a type-expression recursively, so that types like map[string][]*pkg.T can be rendered as-is
+// @JsonStruct()
+type Type struct {
+	Kind        string      `json:"kind"`
+	Name        string      `json:"name,omitempty"`        // named types only
+	Package     string      `json:"package,omitempty"`     // package-qualifier as used in source
+	PackagePath string      `json:"packagePath,omitempty"` // import-path of the package-qualifier: type-checked parsing also fills it for types of the own package
+	Underlying  string      `json:"underlying,omitempty"`  // named types only, type-checked parsing or typedef in the same package: kind of the underlying type like "string" or "struct"
+	TypeArgs    []Type      `json:"typeArgs,omitempty"`    // instantiated generic types only
+	Elem        *Type       `json:"elem,omitempty"`        // pointer, slice, array and chan
+	Len         string      `json:"len,omitempty"`         // array only
+	Key         *Type       `json:"key,omitempty"`         // map only
+	Value       *Type       `json:"value,omitempty"`       // map only
+	ChanDir     string      `json:"chanDir,omitempty"`     // chan only: "send", "recv" or empty for bidirectional
+	Params      []Field     `json:"params,omitempty"`      // func only
+	Results     []Field     `json:"results,omitempty"`     // func only
+	Fields      []Field     `json:"fields,omitempty"`      // anonymous struct, or embedded struct when type-checked
+	Methods     []Operation `json:"methods,omitempty"`     // anonymous interface only
+}
+
+// @JsonStruct()
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint,omitempty"`
 }
 
 // @JsonStruct()
 type Typedef struct {
-	PackageName string   `json:"packageName"`
-	Filename    string   `json:"filename"`
-	DocLines    []string `json:"docLines,omitempty"`
-	Name        string   `json:"name"`
-	Type        string   `json:"type,omitempty"`
+	PackageName  string       `json:"packageName"`
+	Filename     string       `json:"filename"`
+	Position     Position     `json:"position"`
+	DocLines     []string     `json:"docLines,omitempty"`
+	DocPositions []Position   `json:"docPositions,omitempty"` // positions of the DocLines
+	Name         string       `json:"name"`
+	TypeParams   []TypeParam  `json:"typeParams,omitempty"`
+	IsAlias      bool         `json:"isAlias,omitempty"` // type A = B
+	Type         string       `json:"type,omitempty"`    // the definition as in go source-code: empty for structs and interfaces
+	Definition   *Type        `json:"definition,omitempty"`
+	Underlying   string       `json:"underlying,omitempty"` // kind of the underlying type like "string" or "slice": empty when unknown
+	Operations   []*Operation `json:"operations,omitempty"`
 }
 
 // @JsonStruct()
 type Enum struct {
 	PackageName  string        `json:"packageName"`
 	Filename     string        `json:"filename"`
+	Position     Position      `json:"position"`
 	DocLines     []string      `json:"docLines,omitempty"`
+	DocPositions []Position    `json:"docPositions,omitempty"` // positions of the DocLines
 	Name         string        `json:"name,omitempty"`
 	EnumLiterals []EnumLiteral `json:"enumLiterals,omitempty"`
 	CommentLines []string      `json:"commentLines,omitempty"`
+	Annotations  []Annotation  `json:"annotations,omitempty"` // recognized in DocLines
 }
 
 // @JsonStruct()
 type EnumLiteral struct {
-	Name  string `json:"name"`
-	Value string `json:"value,omitempty"`
+	Position     Position `json:"position"`
+	DocLines     []string `json:"docLines,omitempty"`
+	Name         string   `json:"name"`
+	Value        string   `json:"value,omitempty"` // evaluated constant value: strings are unquoted
+	CommentLines []string `json:"commentLines,omitempty"`
+}
+
+// Constant is a package-level constant that is not part of an enum
+// @JsonStruct()
+type Constant struct {
+	PackageName  string   `json:"packageName"`
+	Filename     string   `json:"filename"`
+	Position     Position `json:"position"`
+	DocLines     []string `json:"docLines,omitempty"`
+	Name         string   `json:"name"`
+	TypeName     string   `json:"typeName,omitempty"` // empty for untyped constants
+	Value        string   `json:"value,omitempty"`    // evaluated constant value: strings are unquoted
+	CommentLines []string `json:"commentLines,omitempty"`
+}
+
+// @JsonStruct()
+type Variable struct {
+	PackageName  string   `json:"packageName"`
+	Filename     string   `json:"filename"`
+	Position     Position `json:"position"`
+	DocLines     []string `json:"docLines,omitempty"`
+	Name         string   `json:"name"`
+	TypeName     string   `json:"typeName,omitempty"` // empty when the type is inferred from the initial value
+	Value        string   `json:"value,omitempty"`    // only when initialized with a constant expression
+	CommentLines []string `json:"commentLines,omitempty"`
+}
+
+// Tag is a decoded key:"name,option,option" pair of a struct-tag
+// @JsonStruct()
+type Tag struct {
+	Key     string   `json:"key"`
+	Name    string   `json:"name,omitempty"`
+	Options []string `json:"options,omitempty"`
+}
+
+// Position is the location of an element in the sources, like token.Position
+// @JsonStruct()
+type Position struct {
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
 }
@@ -0,0 +1,24 @@
+package model
+
+// IsMethod reports whether the operation has a receiver
+func (o Operation) IsMethod() bool {
+	return o.RelatedStruct != nil
+}
+
+// HasValueReceiver reports whether the operation is a method that operates on a copy of its receiver
+func (o Operation) HasValueReceiver() bool {
+	return o.IsMethod() && o.ReceiverKind == ReceiverValue
+}
+
+// ReturnsError reports whether the last output-arg of the operation is an error
+func (o Operation) ReturnsError() bool {
+	return len(o.OutputArgs) > 0 && o.OutputArgs[len(o.OutputArgs)-1].TypeName == "error"
+}
+
+// Constructor returns the detected constructor of the struct, or nil when there is none
+func (s Struct) Constructor() *Operation {
+	if len(s.Constructors) == 0 {
+		return nil
+	}
+	return s.Constructors[0]
+}
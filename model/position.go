@@ -0,0 +1,26 @@
+package model
+
+import "fmt"
+
+// IsValid reports whether the position refers to a line in the sources
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String renders the position the way the go-tools do: file:line:column
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d", p.Line)
+		if p.Column != 0 {
+			s += fmt.Sprintf(":%d", p.Column)
+		}
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
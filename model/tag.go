@@ -0,0 +1,21 @@
+package model
+
+// LookupTag returns the decoded tag with the given key, like reflect.StructTag.Lookup does
+func (f Field) LookupTag(key string) (Tag, bool) {
+	for _, tag := range f.Tags {
+		if tag.Key == key {
+			return tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// HasOption tells if the tag has an option like "omitempty"
+func (t Tag) HasOption(option string) bool {
+	for _, o := range t.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String renders the type as it would appear in go source-code
+func (t Type) String() string {
+	switch t.Kind {
+	case KindPointer:
+		return "*" + t.Elem.String()
+	case KindSlice:
+		return "[]" + t.Elem.String()
+	case KindArray:
+		return fmt.Sprintf("[%s]%s", t.Len, t.Elem.String())
+	case KindMap:
+		return fmt.Sprintf("map[%s]%s", t.Key.String(), t.Value.String())
+	case KindChan:
+		switch t.ChanDir {
+		case "send":
+			return "chan<- " + t.Elem.String()
+		case "recv":
+			return "<-chan " + t.Elem.String()
+		}
+		return "chan " + t.Elem.String()
+	case KindFunc:
+		return "func" + signatureString(t.Params, t.Results)
+	case KindStruct:
+		fields := []string{}
+		for _, f := range t.Fields {
+			fields = append(fields, strings.TrimSpace(f.Name+" "+fieldTypeString(f)))
+		}
+		return fmt.Sprintf("struct{%s}", strings.Join(fields, "; "))
+	case KindInterface:
+		methods := []string{}
+		for _, m := range t.Methods {
+			methods = append(methods, m.Name+signatureString(m.InputArgs, m.OutputArgs))
+		}
+		return fmt.Sprintf("interface{%s}", strings.Join(methods, "; "))
+	}
+
+	name := t.Name
+	if t.Package != "" {
+		name = t.Package + "." + name
+	}
+	if len(t.TypeArgs) > 0 {
+		typeArgs := []string{}
+		for _, ta := range t.TypeArgs {
+			typeArgs = append(typeArgs, ta.String())
+		}
+		name = fmt.Sprintf("%s[%s]", name, strings.Join(typeArgs, ", "))
+	}
+	return name
+}
+
+func signatureString(params []Field, results []Field) string {
+	signature := "(" + fieldListString(params) + ")"
+	if len(results) == 1 && results[0].Name == "" {
+		return signature + " " + fieldTypeString(results[0])
+	}
+	if len(results) > 0 {
+		return signature + " (" + fieldListString(results) + ")"
+	}
+	return signature
+}
+
+func fieldListString(fields []Field) string {
+	args := []string{}
+	for _, f := range fields {
+		args = append(args, strings.TrimSpace(f.Name+" "+fieldTypeString(f)))
+	}
+	return strings.Join(args, ", ")
+}
+
+// TypeString renders the type of the field as it would appear in go source-code
+func (f Field) TypeString() string {
+	return fieldTypeString(f)
+}
+
+func fieldTypeString(f Field) string {
+	if f.Type != nil {
+		typeString := f.Type.String()
+		if f.IsVariadic && f.Type.Kind == KindSlice {
+			typeString = "..." + f.Type.Elem.String()
+		}
+		return typeString
+	}
+	typeName := f.TypeName
+	if f.IsPointer {
+		typeName = "*" + typeName
+	}
+	if f.IsVariadic {
+		return "..." + typeName
+	}
+	if f.IsSlice {
+		typeName = "[]" + typeName
+	}
+	return typeName
+}
@@ -0,0 +1,16 @@
+package annotationLines
+
+// @RestService( path = "/api", protectd = true )
+type (
+	Service struct {
+		Name string // @RestService( pth = "/other" )
+	}
+)
+
+// @RestOperation( method = "GET", path = "/person",
+// form = "maybe" )
+//
+//go:noinline
+func (s *Service) getPerson() error {
+	return nil
+}
@@ -0,0 +1,12 @@
+package annotations
+
+// @RestService( path = "/api" )
+type Service struct {
+	// @RestService( path = "/other" )
+	Name string
+}
+
+// @RestOperation( method = "GET", path = "/person" )
+func (s *Service) getPerson() error {
+	return nil
+}
@@ -0,0 +1,5 @@
+package buildtags
+
+type Common struct {
+	Name string
+}
@@ -0,0 +1,7 @@
+//go:build !pro
+
+package buildtags
+
+type Free struct {
+	Limit int
+}
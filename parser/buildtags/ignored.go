@@ -0,0 +1,8 @@
+//go:build ignore
+// +build ignore
+
+package buildtags
+
+type Ignored struct {
+	Obsolete bool
+}
@@ -0,0 +1,7 @@
+//go:build pro && !appengine
+
+package buildtags
+
+type Pro struct {
+	Support bool
+}
@@ -0,0 +1,52 @@
+package constants
+
+import "time"
+
+type Weekday int
+
+const (
+	_ Weekday = iota
+	// First day of the week
+	Monday
+	Tuesday // after the weekend
+	Wednesday
+)
+
+type Permission uint
+
+const (
+	Read Permission = 1 << iota
+	Write
+	Execute
+)
+
+const (
+	Thursday Weekday = Wednesday + 1
+	Friday   Weekday = Thursday + 1
+)
+
+// MaxRetries limits the attempts
+const MaxRetries = 3
+
+const (
+	Prefix      = "api/"
+	Timeout     = 5 * time.Second
+	KB      int = 1 << 10
+	Ratio       = 1.5
+)
+
+// DefaultPath is where it all starts
+var DefaultPath = Prefix + "v1"
+
+var (
+	counter int
+	started time.Time
+)
+
+func init() {
+	const local Weekday = 7
+	var ignored = local
+	_ = ignored
+	counter = MaxRetries
+	started = time.Now()
+}
@@ -0,0 +1,48 @@
+package embedded
+
+import "github.com/MarcGrol/golangAnnotations/parser/structs"
+
+type Audit struct {
+	Created string
+	Tags    []string
+}
+
+func (a Audit) Describe() string {
+	return a.Created
+}
+
+type Base struct {
+	Audit
+	ID     string
+	Labels []string
+}
+
+func (b *Base) Validate() error {
+	return nil
+}
+
+type Other struct {
+	ID string
+}
+
+type Document struct {
+	Base
+	*Other
+	*structs.YetAnotherStruct
+	Name string
+}
+
+type Left struct {
+	Audit
+	Side string
+}
+
+type Right struct {
+	Audit
+}
+
+// Diamond embeds Audit twice at the same depth: its members are ambiguous
+type Diamond struct {
+	Left
+	Right
+}
@@ -0,0 +1,25 @@
+package fieldtypes
+
+import (
+	"io"
+
+	"github.com/MarcGrol/golangAnnotations/parser/structs"
+)
+
+type Foo struct {
+	Name string
+}
+
+type Complex struct {
+	Matrix     [][]string
+	Lookup     map[string][]Foo
+	Optional   *[]Foo
+	Events     chan Foo
+	Incoming   <-chan *Foo
+	Callback   func(in Foo, w io.Writer) (int, error)
+	Hash       [32]byte
+	Anonymous  struct{ X, Y int }
+	Closer     interface{ Close() error }
+	Foreign    map[structs.ColorType]*structs.YetAnotherStruct
+	ForeignPtr []*structs.YetAnotherStruct
+}
@@ -0,0 +1,43 @@
+package generics
+
+// docline for Page
+type Page[T any] struct {
+	Items    []T
+	Selected *T
+	Total    int
+}
+
+// docline for Pair
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// docline for Lookup
+type Lookup[K comparable, V any] interface {
+	// docline for interface method get
+	get(key K) (V, bool)
+}
+
+type Catalog struct {
+	Products Page[string]
+	Prices   []*Pair[string, int]
+}
+
+// docline for first
+func (p *Page[T]) first() (T, bool) {
+	if len(p.Items) == 0 {
+		var none T
+		return none, false
+	}
+	return p.Items[0], true
+}
+
+// docline for Map
+func Map[From, To any](in []From, convert func(From) To) []To {
+	out := make([]To, 0, len(in))
+	for _, i := range in {
+		out = append(out, convert(i))
+	}
+	return out
+}
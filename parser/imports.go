@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"go/types"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// resolveDotImports qualifies the named types that are used without package-qualifier, but that are neither
+// declared in the package nor predeclared: in a file with a single dot-import these come from that package
+func resolveDotImports(visitor *astVisitor) {
+	dotImports := map[string][]string{}
+	for _, mImport := range visitor.ImportSpecs {
+		if mImport.IsDot() {
+			dotImports[mImport.Filename] = append(dotImports[mImport.Filename], mImport.Path)
+		}
+	}
+	if len(dotImports) == 0 {
+		return
+	}
+
+	declared := map[string]bool{}
+	for _, mTypedef := range visitor.Typedefs {
+		declared[mTypedef.Name] = true
+	}
+
+	for idx := range visitor.Structs {
+		mStruct := &visitor.Structs[idx]
+		if importPath, ok := singleDotImport(dotImports, mStruct.Filename); ok {
+			resolveDotImportedFields(mStruct.Fields, importPath, withTypeParams(declared, mStruct.TypeParams))
+		}
+	}
+	for idx := range visitor.Interfaces {
+		mInterface := &visitor.Interfaces[idx]
+		if importPath, ok := singleDotImport(dotImports, mInterface.Filename); ok {
+			resolveDotImportedOperations(mInterface.Methods, importPath, withTypeParams(declared, mInterface.TypeParams))
+		}
+	}
+	for idx := range visitor.Operations {
+		mOperation := &visitor.Operations[idx]
+		if importPath, ok := singleDotImport(dotImports, mOperation.Filename); ok {
+			resolveDotImportedOperations(visitor.Operations[idx:idx+1], importPath, withTypeParams(declared, mOperation.TypeParams))
+		}
+	}
+	for idx := range visitor.Typedefs {
+		mTypedef := &visitor.Typedefs[idx]
+		if importPath, ok := singleDotImport(dotImports, mTypedef.Filename); ok {
+			resolveDotImportedType(mTypedef.Definition, importPath, withTypeParams(declared, mTypedef.TypeParams))
+		}
+	}
+}
+
+func singleDotImport(dotImports map[string][]string, filename string) (string, bool) {
+	importPaths := dotImports[filename]
+	if len(importPaths) != 1 {
+		// with multiple dot-imports only the type-checker knows where a name comes from
+		return "", false
+	}
+	return importPaths[0], true
+}
+
+func withTypeParams(declared map[string]bool, typeParams []model.TypeParam) map[string]bool {
+	if len(typeParams) == 0 {
+		return declared
+	}
+	names := map[string]bool{}
+	for name := range declared {
+		names[name] = true
+	}
+	for _, typeParam := range typeParams {
+		names[typeParam.Name] = true
+	}
+	return names
+}
+
+func resolveDotImportedOperations(mOperations []model.Operation, importPath string, declared map[string]bool) {
+	for idx := range mOperations {
+		resolveDotImportedFields(mOperations[idx].InputArgs, importPath, declared)
+		resolveDotImportedFields(mOperations[idx].OutputArgs, importPath, declared)
+	}
+}
+
+func resolveDotImportedFields(mFields []model.Field, importPath string, declared map[string]bool) {
+	for idx := range mFields {
+		mField := &mFields[idx]
+		resolveDotImportedType(mField.Type, importPath, declared)
+
+		elem := mField.Type
+		for elem != nil && (elem.Kind == model.KindPointer || elem.Kind == model.KindSlice || elem.Kind == model.KindArray) {
+			elem = elem.Elem
+		}
+		if mField.PackageName == "" && elem != nil && elem.Kind == model.KindNamed && elem.PackagePath == importPath {
+			mField.PackageName = importPath
+		}
+	}
+}
+
+func resolveDotImportedType(mType *model.Type, importPath string, declared map[string]bool) {
+	if mType == nil {
+		return
+	}
+	if mType.Kind == model.KindNamed && mType.Package == "" && mType.PackagePath == "" &&
+		!declared[mType.Name] && types.Universe.Lookup(mType.Name) == nil {
+		mType.PackagePath = importPath
+	}
+	for idx := range mType.TypeArgs {
+		resolveDotImportedType(&mType.TypeArgs[idx], importPath, declared)
+	}
+	resolveDotImportedType(mType.Elem, importPath, declared)
+	resolveDotImportedType(mType.Key, importPath, declared)
+	resolveDotImportedType(mType.Value, importPath, declared)
+	resolveDotImportedFields(mType.Params, importPath, declared)
+	resolveDotImportedFields(mType.Results, importPath, declared)
+	resolveDotImportedFields(mType.Fields, importPath, declared)
+	resolveDotImportedOperations(mType.Methods, importPath, declared)
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// ParseFS parses the go-files in dirName of fsys like ParseSourceDir does for a directory on disk,
+// so that tools can provide sources that have not been saved, for example via fstest.MapFS
+func ParseFS(fsys fs.FS, dirName string, includeRegex string, excludeRegex string, buildTags ...string) (model.ParsedSources, error) {
+	var includePattern = regexp.MustCompile(includeRegex)
+	var excludePattern = regexp.MustCompile(excludeRegex)
+
+	entries, err := fs.ReadDir(fsys, dirName)
+	if err != nil {
+		log.Printf("error listing dir %s: %s", dirName, err.Error())
+		return model.ParsedSources{}, err
+	}
+	sources := map[string][]byte{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || excludePattern.MatchString(name) || !includePattern.MatchString(name) {
+			continue
+		}
+		filename := path.Join(dirName, name)
+		src, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			log.Printf("error reading src %s: %s", filename, err.Error())
+			return model.ParsedSources{}, err
+		}
+		sources[filename] = src
+	}
+	return ParseSources(sources, buildTags...)
+}
+
+// ParseSources parses sources held in memory, like the unsaved buffers of an editor: the keys are
+// the filenames as they appear in the model. Files that are excluded by their build-constraints are skipped.
+func ParseSources(sources map[string][]byte, buildTags ...string) (model.ParsedSources, error) {
+	filenames := []string{}
+	for filename := range sources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	fileSet := token.NewFileSet()
+	packages := map[string]map[string]*ast.File{}
+	for _, filename := range filenames {
+		src := sources[filename]
+		if !matchesSourceBuildConstraints(buildTags, filename, src) {
+			continue
+		}
+		file, err := parser.ParseFile(fileSet, filename, src, parser.ParseComments)
+		if err != nil {
+			log.Printf("error parsing src %s: %s", filename, err.Error())
+			return model.ParsedSources{}, err
+		}
+		if packages[file.Name.Name] == nil {
+			packages[file.Name.Name] = map[string]*ast.File{}
+		}
+		packages[file.Name.Name][filename] = file
+	}
+
+	fileSources := map[string]model.ParsedSources{}
+	for _, files := range packages {
+		parsePackageFiles(fileSet, files, fileSources, nil)
+	}
+	return assembleParsedSources(fileSources), nil
+}
+
+func matchesSourceBuildConstraints(buildTags []string, filename string, src []byte) bool {
+	buildContext := newBuildContext(buildTags)
+	buildContext.OpenFile = func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(src)), nil
+	}
+	return matchesBuildConstraints(buildContext, path.Dir(filename), path.Base(filename))
+}
@@ -0,0 +1,4 @@
+// Package packages is an example of a package with default annotations
+//
+// @Event( aggregate = "Tour" )
+package packages
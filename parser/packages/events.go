@@ -0,0 +1,6 @@
+package packages
+
+// @Event()
+type TourCreated struct {
+	Year int
+}
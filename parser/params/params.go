@@ -0,0 +1,19 @@
+package params
+
+import (
+	"context"
+	"io"
+)
+
+type Option func(*Service)
+
+type Service struct {
+}
+
+func (s *Service) subscribe(ctx context.Context, cb func(context.Context, ...string) error, opts ...Option) error {
+	return nil
+}
+
+func (s *Service) write(w io.Writer, f interface{ Flush() error }, names ...*string) (int, error) {
+	return 0, nil
+}
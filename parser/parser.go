@@ -3,15 +3,23 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/constant"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/diagnostics"
 	"github.com/MarcGrol/golangAnnotations/model"
 	"github.com/MarcGrol/golangAnnotations/parser/parserUtil"
 )
@@ -21,58 +29,134 @@ var (
 )
 
 type myParser struct {
+	buildTags []string
+	cache     *sourceCache
 }
 
-func New() parserUtil.Parser {
-	return &myParser{}
+// New returns a parser that only includes the files that "go build -tags" would compile with the given build-tags
+func New(buildTags ...string) parserUtil.Parser {
+	return &myParser{
+		buildTags: buildTags,
+	}
+}
+
+// NewCached returns a parser like New that keeps its results per file in cacheDir: files that did not change
+// since a previous run with the same tool-version are not visited again
+func NewCached(cacheDir string, toolVersion string, buildTags ...string) parserUtil.Parser {
+	return &myParser{
+		buildTags: buildTags,
+		cache: &sourceCache{
+			dir:         cacheDir,
+			toolVersion: toolVersion,
+		},
+	}
 }
 
 func (p *myParser) ParseSourceDir(dirName string, includeRegex string, excludeRegex string) (model.ParsedSources, error) {
 	if debugAstOfSources {
 		dumpFilesInDir(dirName)
 	}
-	packages, err := parseDir(dirName, includeRegex, excludeRegex)
+
+	var packageCache *packageCache
+	if p.cache != nil {
+		filenames, err := listSourceFiles(dirName, includeRegex, excludeRegex, p.buildTags)
+		if err != nil {
+			log.Printf("error listing dir %s: %s", dirName, err.Error())
+			return model.ParsedSources{}, err
+		}
+		packageCache = p.cache.forPackage(filenames)
+		if packageCache != nil {
+			fileSources, found := packageCache.lookupAll()
+			if found {
+				return assembleParsedSources(fileSources), nil
+			}
+		}
+	}
+
+	fileSet := token.NewFileSet()
+	packages, err := parseDir(fileSet, dirName, includeRegex, excludeRegex, p.buildTags)
 	if err != nil {
 		log.Printf("error parsing dir %s: %s", dirName, err.Error())
 		return model.ParsedSources{}, err
 	}
 
-	v := &astVisitor{
-		Imports: map[string]string{},
-	}
+	fileSources := map[string]model.ParsedSources{}
 	for _, aPackage := range packages {
-		for _, fileEntry := range sortedFileEntries(aPackage.Files) {
-			v.CurrentFilename = fileEntry.key
-
-			appEngineOnly := true
-			for _, commentGroup := range fileEntry.file.Comments {
-				if commentGroup != nil {
-					for _, comment := range commentGroup.List {
-						if comment != nil && comment.Text == "// +build !appengine" {
-							appEngineOnly = false
-						}
-					}
-				}
-			}
-			if appEngineOnly {
-				ast.Walk(v, &fileEntry.file)
+		parsePackageFiles(fileSet, aPackage.Files, fileSources, packageCache)
+	}
+
+	return assembleParsedSources(fileSources), nil
+}
+
+// parsePackageFiles adds the sources of each of the files of a single package to fileSources:
+// the files that did not change are taken from the cache, when given
+func parsePackageFiles(fileSet *token.FileSet, files map[string]*ast.File, fileSources map[string]model.ParsedSources, cache *packageCache) {
+	typesInfo := evaluateConstants(fileSet, files)
+	constants := ""
+	if cache != nil {
+		constants = constantsKey(typesInfo)
+	}
+	for _, fileEntry := range sortedFileEntries(files) {
+		if cache != nil {
+			sources, found := cache.lookupFile(fileEntry.key, constants)
+			if found {
+				fileSources[fileEntry.key] = sources
+				continue
 			}
 		}
+		v := &astVisitor{
+			FileSet:         fileSet,
+			TypesInfo:       typesInfo,
+			CurrentFilename: fileEntry.key,
+			Imports:         map[string]string{},
+		}
+		ast.Walk(v, &fileEntry.file)
+		fileSources[fileEntry.key] = v.parsedSources()
+		if cache != nil {
+			cache.storeFile(fileEntry.key, constants, fileSources[fileEntry.key])
+		}
 	}
+}
+
+// assembleParsedSources combines the sources of the individual files of a package
+func assembleParsedSources(fileSources map[string]model.ParsedSources) model.ParsedSources {
+	filenames := []string{}
+	for filename := range fileSources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	v := &astVisitor{}
+	for _, filename := range filenames {
+		sources := fileSources[filename]
+		v.Structs = append(v.Structs, sources.Structs...)
+		v.Operations = append(v.Operations, sources.Operations...)
+		v.Interfaces = append(v.Interfaces, sources.Interfaces...)
+		v.Typedefs = append(v.Typedefs, sources.Typedefs...)
+		for _, mEnum := range sources.Enums {
+			v.addEnum(mEnum)
+		}
+		v.Constants = append(v.Constants, sources.Constants...)
+		v.Variables = append(v.Variables, sources.Variables...)
+		v.ImportSpecs = append(v.ImportSpecs, sources.Imports...)
+		v.Packages = append(v.Packages, sources.Packages...)
+	}
+
+	resolveDotImports(v)
+
+	resolveTypedefKinds(v)
 
 	embedOperationsInStructs(v)
 
-	embedTypedefDocLinesInEnum(v)
+	embedOperationsInTypedefs(v)
 
-	result := model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
-	}
+	embedPromotedMembersInStructs(v)
+
+	embedTypedefDocLinesInEnum(v)
 
-	return result, nil
+	parsedSources := v.parsedSources()
+	attachAnnotations(&parsedSources)
+	return parsedSources
 }
 
 func parseSourceFile(srcFilename string) (model.ParsedSources, error) {
@@ -86,23 +170,28 @@ func parseSourceFile(srcFilename string) (model.ParsedSources, error) {
 		return model.ParsedSources{}, err
 	}
 	v := &astVisitor{
-		Imports: map[string]string{},
+		FileSet:   fileSet,
+		TypesInfo: evaluateConstants(fileSet, map[string]*ast.File{srcFilename: file}),
+		Imports:   map[string]string{},
 	}
 	v.CurrentFilename = srcFilename
 	ast.Walk(v, file)
 
+	resolveDotImports(v)
+
+	resolveTypedefKinds(v)
+
 	embedOperationsInStructs(v)
 
+	embedOperationsInTypedefs(v)
+
+	embedPromotedMembersInStructs(v)
+
 	embedTypedefDocLinesInEnum(v)
 
-	result := model.ParsedSources{
-		Structs:    v.Structs,
-		Operations: v.Operations,
-		Interfaces: v.Interfaces,
-		Typedefs:   v.Typedefs,
-		Enums:      v.Enums,
-	}
-	return result, nil
+	parsedSources := v.parsedSources()
+	attachAnnotations(&parsedSources)
+	return parsedSources, nil
 }
 
 type fileEntry struct {
@@ -144,15 +233,200 @@ func embedOperationsInStructs(visitor *astVisitor) {
 		mStructMap[(&visitor.Structs[idx]).Name] = &visitor.Structs[idx]
 	}
 	for idx := range visitor.Operations {
-		mOperation := visitor.Operations[idx]
-		if mOperation.RelatedStruct != nil {
-			mStruct, ok := mStructMap[(*mOperation.RelatedStruct).TypeName]
+		if visitor.Operations[idx].RelatedStruct != nil {
+			mStruct, ok := mStructMap[(*visitor.Operations[idx].RelatedStruct).TypeName]
 			if ok {
+				embedTypeParamConstraints(&visitor.Operations[idx], *mStruct)
+				mOperation := visitor.Operations[idx]
 				mStruct.Operations = append(mStruct.Operations, &mOperation)
 			}
+		} else {
+			for sIdx := range visitor.Structs {
+				mStruct := &visitor.Structs[sIdx]
+				if isConstructorOf(visitor.Operations[idx], *mStruct) {
+					mOperation := visitor.Operations[idx]
+					mStruct.Constructors = append(mStruct.Constructors, &mOperation)
+				}
+			}
+		}
+	}
+
+}
+
+// isConstructorOf recognizes the NewX convention: func NewX(...) (*X[, error])
+func isConstructorOf(mOperation model.Operation, mStruct model.Struct) bool {
+	if mOperation.Name != "New"+toFirstUpper(mStruct.Name) || len(mOperation.TypeParams) > 0 {
+		return false
+	}
+	switch len(mOperation.OutputArgs) {
+	case 1:
+	case 2:
+		if !mOperation.ReturnsError() {
+			return false
+		}
+	default:
+		return false
+	}
+	result := mOperation.OutputArgs[0]
+	return result.TypeName == mStruct.Name && !result.IsSlice
+}
+
+func embedPromotedMembersInStructs(visitor *astVisitor) {
+	mStructMap := make(map[string]*model.Struct)
+	for idx := range visitor.Structs {
+		mStructMap[(&visitor.Structs[idx]).Name] = &visitor.Structs[idx]
+	}
+	for idx := range visitor.Structs {
+		mStruct := &visitor.Structs[idx]
+		mStruct.PromotedFields, mStruct.PromotedOperations = extractPromotedMembers(*mStruct, mStructMap)
+	}
+}
+
+type embedding struct {
+	field model.Field
+	via   []string
+}
+
+func extractPromotedMembers(mStruct model.Struct, mStructMap map[string]*model.Struct) ([]model.Field, []*model.Operation) {
+	promotedFields := []model.Field{}
+	promotedOperations := []*model.Operation{}
+
+	// Members at a shallower depth hide members with the same name at a deeper depth
+	hidden := map[string]bool{}
+	for _, f := range mStruct.Fields {
+		hidden[f.Name] = true
+	}
+	for _, o := range mStruct.Operations {
+		hidden[o.Name] = true
+	}
+
+	visited := map[string]bool{mStruct.Name: true}
+	current := collectEmbeddings(mStruct.Fields, nil)
+	for len(current) > 0 {
+		next := []embedding{}
+		names := []string{}
+		fieldsPerName := map[string][]model.Field{}
+		operationsPerName := map[string][]*model.Operation{}
+		for _, e := range current {
+			// A type embedded at a shallower depth hides it here. Embedded more than once at the same depth,
+			// its members are found more than once, so these are ambiguous.
+			if visited[e.field.TypeName] {
+				continue
+			}
+
+			fields, operations := extractEmbeddedMembers(e.field, mStructMap)
+			for _, f := range fields {
+				f.PromotedVia = e.via
+				if len(fieldsPerName[f.Name]) == 0 && len(operationsPerName[f.Name]) == 0 {
+					names = append(names, f.Name)
+				}
+				fieldsPerName[f.Name] = append(fieldsPerName[f.Name], f)
+			}
+			for _, o := range operations {
+				if len(fieldsPerName[o.Name]) == 0 && len(operationsPerName[o.Name]) == 0 {
+					names = append(names, o.Name)
+				}
+				operationsPerName[o.Name] = append(operationsPerName[o.Name], o)
+			}
+			next = append(next, collectEmbeddings(fields, e.via)...)
+		}
+		for _, e := range current {
+			visited[e.field.TypeName] = true
+		}
+
+		for _, name := range names {
+			if hidden[name] {
+				continue
+			}
+			hidden[name] = true
+
+			// Same name at the same depth is ambiguous: neither gets promoted
+			if len(fieldsPerName[name])+len(operationsPerName[name]) == 1 {
+				promotedFields = append(promotedFields, fieldsPerName[name]...)
+				promotedOperations = append(promotedOperations, operationsPerName[name]...)
+			}
+		}
+		current = next
+	}
+
+	if len(promotedFields) == 0 {
+		promotedFields = nil
+	}
+	if len(promotedOperations) == 0 {
+		promotedOperations = nil
+	}
+	return promotedFields, promotedOperations
+}
+
+func collectEmbeddings(fields []model.Field, via []string) []embedding {
+	embeddings := []embedding{}
+	for _, f := range fields {
+		if f.IsEmbedded {
+			step := f.Name
+			if f.IsPointer {
+				step = "*" + step
+			}
+			embeddings = append(embeddings, embedding{
+				field: f,
+				via:   append(append([]string{}, via...), step),
+			})
+		}
+	}
+	return embeddings
+}
+
+func extractEmbeddedMembers(field model.Field, mStructMap map[string]*model.Struct) ([]model.Field, []*model.Operation) {
+	if field.PackageName == "" {
+		mStruct, ok := mStructMap[embeddedFieldName(field.TypeName)]
+		if ok {
+			return mStruct.Fields, mStruct.Operations
+		}
+	}
+
+	// Structs in other packages are only known when type-checked
+	mType := field.Type
+	if mType != nil && mType.Kind == model.KindPointer {
+		mType = mType.Elem
+	}
+	if mType != nil {
+		return mType.Fields, nil
+	}
+	return nil, nil
+}
+
+func embedTypeParamConstraints(mOperation *model.Operation, mStruct model.Struct) {
+	// Receiver type-parameters are positional: func (p *Page[X]) uses the constraint of the first type-parameter of Page
+	for idx := range mOperation.TypeParams {
+		if idx < len(mStruct.TypeParams) {
+			mOperation.TypeParams[idx].Constraint = mStruct.TypeParams[idx].Constraint
 		}
 	}
+}
+
+// evaluateConstants type-checks the files of a package on a best-effort basis: imports are not resolved,
+// but that suffices to evaluate the constant expressions (like iota) that only refer to the package itself
+func evaluateConstants(fileSet *token.FileSet, files map[string]*ast.File) *types.Info {
+	fileList := []*ast.File{}
+	for _, fileEntry := range sortedFileEntries(files) {
+		file := fileEntry.file
+		fileList = append(fileList, &file)
+	}
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{
+		IgnoreFuncBodies: true,
+		Error:            func(err error) {}, // unresolved imports are to be expected
+	}
+	conf.Check("", fileSet, fileList, info)
+	return info
+}
 
+// attachAnnotations resolves the annotations of the registered generators once, so that these are on the
+// model for the generators and for callers that use the parser as library
+func attachAnnotations(parsedSources *model.ParsedSources) {
+	annotation.AttachAnnotations(annotation.NewRegistry(annotation.RegisteredDescriptors()), parsedSources)
 }
 
 func embedTypedefDocLinesInEnum(visitor *astVisitor) {
@@ -160,26 +434,18 @@ func embedTypedefDocLinesInEnum(visitor *astVisitor) {
 		for _, typedef := range visitor.Typedefs {
 			if typedef.Name == mEnum.Name {
 				visitor.Enums[idx].DocLines = typedef.DocLines
+				visitor.Enums[idx].DocPositions = typedef.DocPositions
 				break
 			}
 		}
 	}
 }
 
-func parseDir(dirName string, includeRegex string, excludeRegex string) (map[string]*ast.Package, error) {
-	var includePattern = regexp.MustCompile(includeRegex)
-	var excludePattern = regexp.MustCompile(excludeRegex)
-
-	fileSet := token.NewFileSet()
+func parseDir(fileSet *token.FileSet, dirName string, includeRegex string, excludeRegex string, buildTags []string) (map[string]*ast.Package, error) {
 	packageMap, err := parser.ParseDir(
 		fileSet,
 		dirName,
-		func(fi os.FileInfo) bool {
-			if excludePattern.MatchString(fi.Name()) {
-				return false
-			}
-			return includePattern.MatchString(fi.Name())
-		},
+		sourceFileFilter(dirName, includeRegex, excludeRegex, buildTags),
 		parser.ParseComments)
 	if err != nil {
 		log.Printf("error parsing dir %s: %s", dirName, err.Error())
@@ -189,6 +455,55 @@ func parseDir(dirName string, includeRegex string, excludeRegex string) (map[str
 	return packageMap, nil
 }
 
+// listSourceFiles returns the same files as parseDir would parse
+func listSourceFiles(dirName string, includeRegex string, excludeRegex string, buildTags []string) ([]string, error) {
+	fileInfos, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		return nil, err
+	}
+	filter := sourceFileFilter(dirName, includeRegex, excludeRegex, buildTags)
+	filenames := []string{}
+	for _, fi := range fileInfos {
+		if !fi.IsDir() && strings.HasSuffix(fi.Name(), ".go") && filter(fi) {
+			filenames = append(filenames, filepath.Join(dirName, fi.Name()))
+		}
+	}
+	return filenames, nil
+}
+
+func sourceFileFilter(dirName string, includeRegex string, excludeRegex string, buildTags []string) func(fi os.FileInfo) bool {
+	var includePattern = regexp.MustCompile(includeRegex)
+	var excludePattern = regexp.MustCompile(excludeRegex)
+
+	buildContext := newBuildContext(buildTags)
+
+	return func(fi os.FileInfo) bool {
+		if excludePattern.MatchString(fi.Name()) {
+			return false
+		}
+		if !includePattern.MatchString(fi.Name()) {
+			return false
+		}
+		return matchesBuildConstraints(buildContext, dirName, fi.Name())
+	}
+}
+
+func newBuildContext(buildTags []string) build.Context {
+	buildContext := build.Default
+	buildContext.BuildTags = buildTags
+	return buildContext
+}
+
+// matchesBuildConstraints evaluates the //go:build and +build lines and the _GOOS_GOARCH suffix of the file-name
+func matchesBuildConstraints(buildContext build.Context, dirName string, filename string) bool {
+	match, err := buildContext.MatchFile(dirName, filename)
+	if err != nil {
+		log.Printf("error evaluating build-constraints of %s: %s", filepath.Join(dirName, filename), err.Error())
+		return false
+	}
+	return match
+}
+
 func dumpFile(srcFilename string) {
 	fileSet := token.NewFileSet()
 	file, err := parser.ParseFile(fileSet, srcFilename, nil, parser.ParseComments)
@@ -217,15 +532,35 @@ func dumpFilesInDir(dirName string) {
 }
 
 type astVisitor struct {
+	FileSet         *token.FileSet
+	TypesInfo       *types.Info
 	CurrentFilename string
 	PackageName     string
 	Filename        string
-	Imports         map[string]string
+	Imports         map[string]string // qualifier to import-path, for the current file
+	ImportSpecs     []model.Import
+	Packages        []model.Package
 	Structs         []model.Struct
 	Operations      []model.Operation
 	Interfaces      []model.Interface
 	Typedefs        []model.Typedef
 	Enums           []model.Enum
+	Constants       []model.Constant
+	Variables       []model.Variable
+}
+
+func (v *astVisitor) parsedSources() model.ParsedSources {
+	return model.ParsedSources{
+		Structs:    v.Structs,
+		Operations: v.Operations,
+		Interfaces: v.Interfaces,
+		Typedefs:   v.Typedefs,
+		Enums:      v.Enums,
+		Constants:  v.Constants,
+		Variables:  v.Variables,
+		Imports:    v.ImportSpecs,
+		Packages:   v.Packages,
+	}
 }
 
 func (v *astVisitor) Visit(node ast.Node) ast.Visitor {
@@ -236,13 +571,14 @@ func (v *astVisitor) Visit(node ast.Node) ast.Visitor {
 		if ok {
 			v.PackageName = packageName
 		}
+		v.parseAsPackageDoc(node)
 
 		// extract all imports into a map
 		v.extractGenDeclImports(node)
 
 		v.parseAsStruct(node)
 		v.parseAsTypedef(node)
-		v.parseAsEnum(node)
+		v.parseAsValues(node)
 		v.parseAsInterFace(node)
 		v.parseAsOperation(node)
 
@@ -252,24 +588,53 @@ func (v *astVisitor) Visit(node ast.Node) ast.Visitor {
 
 func (v *astVisitor) extractGenDeclImports(node ast.Node) {
 	genDecl, ok := node.(*ast.GenDecl)
-	if ok {
-		for _, spec := range genDecl.Specs {
-			importSpec, ok := spec.(*ast.ImportSpec)
-			if ok {
-				quotedImport := importSpec.Path.Value
-				unquotedImport := strings.Trim(quotedImport, "\"")
-				init, last := filepath.Split(unquotedImport)
-				if init == "" {
-					last = init
-				}
-				v.Imports[last] = unquotedImport
+	if !ok || genDecl.Tok != token.IMPORT {
+		return
+	}
+	for _, spec := range genDecl.Specs {
+		importSpec, ok := spec.(*ast.ImportSpec)
+		if ok {
+			mImport := extractImport(importSpec, v.TypesInfo, v.FileSet)
+			mImport.Filename = v.CurrentFilename
+			v.ImportSpecs = append(v.ImportSpecs, mImport)
+			if mImport.Qualifier != "" {
+				v.Imports[mImport.Qualifier] = mImport.Path
+			}
+		}
+	}
+}
+
+func extractImport(importSpec *ast.ImportSpec, typesInfo *types.Info, fileSet *token.FileSet) model.Import {
+	importPath, err := strconv.Unquote(importSpec.Path.Value)
+	if err != nil {
+		importPath = strings.Trim(importSpec.Path.Value, "\"`")
+	}
+	mImport := model.Import{
+		Position: extractPosition(importSpec.Pos(), fileSet),
+		Path:     importPath,
+	}
+	if importSpec.Name != nil {
+		mImport.Name = importSpec.Name.Name
+	}
+	switch {
+	case mImport.IsDot() || mImport.IsBlank():
+	case mImport.Name != "":
+		mImport.Qualifier = mImport.Name
+	default:
+		mImport.Qualifier = model.AssumedPackageName(importPath)
+		// The package-clause of the imported package is only known when it was resolved by the type-checker
+		if typesInfo != nil {
+			pkgName, ok := typesInfo.Implicits[importSpec].(*types.PkgName)
+			if ok && pkgName.Imported() != nil && pkgName.Imported().Complete() {
+				mImport.Qualifier = pkgName.Imported().Name()
 			}
 		}
 	}
+	return mImport
 }
 
 func (v *astVisitor) parseAsStruct(node ast.Node) {
-	mStruct := extractGenDeclForStruct(node, v.Imports)
+	mStruct := extractGenDeclForStruct(node, v.Imports, v.FileSet)
 	if mStruct != nil {
 		mStruct.PackageName = v.PackageName
 		mStruct.Filename = v.CurrentFilename
@@ -278,26 +643,61 @@ func (v *astVisitor) parseAsStruct(node ast.Node) {
 }
 
 func (v *astVisitor) parseAsTypedef(node ast.Node) {
-	mTypedef := extractGenDeclForTypedef(node)
-	if mTypedef != nil {
+	for _, mTypedef := range extractGenDeclForTypedefs(node, v.Imports, v.FileSet) {
 		mTypedef.PackageName = v.PackageName
 		mTypedef.Filename = v.CurrentFilename
-		v.Typedefs = append(v.Typedefs, *mTypedef)
+		v.Typedefs = append(v.Typedefs, mTypedef)
 	}
 }
 
-func (v *astVisitor) parseAsEnum(node ast.Node) {
-	mEnum := extractGenDeclForEnum(node)
-	if mEnum != nil {
-		mEnum.PackageName = v.PackageName
-		mEnum.Filename = v.CurrentFilename
-		v.Enums = append(v.Enums, *mEnum)
+func (v *astVisitor) parseAsValues(node ast.Node) {
+	// only package-level constants and variables: not the ones within functions
+	file, ok := node.(*ast.File)
+	if !ok {
+		return
+	}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		switch genDecl.Tok {
+		case token.CONST:
+			mEnums, mConstants := extractGenDeclForConstants(genDecl, v.TypesInfo, v.FileSet)
+			for _, mEnum := range mEnums {
+				mEnum.PackageName = v.PackageName
+				mEnum.Filename = v.CurrentFilename
+				v.addEnum(mEnum)
+			}
+			for _, mConstant := range mConstants {
+				mConstant.PackageName = v.PackageName
+				mConstant.Filename = v.CurrentFilename
+				v.Constants = append(v.Constants, mConstant)
+			}
+		case token.VAR:
+			for _, mVariable := range extractGenDeclForVariables(genDecl, v.TypesInfo, v.FileSet) {
+				mVariable.PackageName = v.PackageName
+				mVariable.Filename = v.CurrentFilename
+				v.Variables = append(v.Variables, mVariable)
+			}
+		}
 	}
 }
 
+// addEnum assembles the literals of an enum that are spread over multiple const-blocks
+func (v *astVisitor) addEnum(mEnum model.Enum) {
+	for idx := range v.Enums {
+		if v.Enums[idx].Name == mEnum.Name {
+			v.Enums[idx].EnumLiterals = append(v.Enums[idx].EnumLiterals, mEnum.EnumLiterals...)
+			return
+		}
+	}
+	v.Enums = append(v.Enums, mEnum)
+}
+
 func (v *astVisitor) parseAsInterFace(node ast.Node) {
 	// if interfaces, get its methods
-	mInterface := extractGenDecForInterface(node, v.Imports)
+	mInterface := extractGenDecForInterface(node, v.Imports, v.FileSet)
 	if mInterface != nil {
 		mInterface.PackageName = v.PackageName
 		mInterface.Filename = v.CurrentFilename
@@ -307,7 +707,7 @@ func (v *astVisitor) parseAsInterFace(node ast.Node) {
 
 func (v *astVisitor) parseAsOperation(node ast.Node) {
 	// if mOperation, get its signature
-	mOperation := extractOperation(node, v.Imports)
+	mOperation := extractOperation(node, v.Imports, v.FileSet)
 	if mOperation != nil {
 		mOperation.PackageName = v.PackageName
 		mOperation.Filename = v.CurrentFilename
@@ -315,48 +715,49 @@ func (v *astVisitor) parseAsOperation(node ast.Node) {
 	}
 }
 
-func extractGenDeclForStruct(node ast.Node, imports map[string]string) *model.Struct {
+func extractGenDeclForStruct(node ast.Node, imports map[string]string, fileSet *token.FileSet) *model.Struct {
 	genDecl, ok := node.(*ast.GenDecl)
 	if ok {
 		// Continue parsing to see if it a struct
-		mStruct := extractSpecsForStruct(genDecl.Specs, imports)
+		mStruct := extractSpecsForStruct(genDecl.Specs, imports, fileSet)
 		if mStruct != nil {
 			// Docline of struct (that could contain annotations) appear far before the details of the struct
 			mStruct.DocLines = extractComments(genDecl.Doc)
+			mStruct.DocPositions = extractCommentPositions(genDecl.Doc, fileSet)
 			return mStruct
 		}
 	}
 	return nil
 }
 
-func extractGenDeclForTypedef(node ast.Node) *model.Typedef {
+func extractGenDeclForTypedefs(node ast.Node, imports map[string]string, fileSet *token.FileSet) []model.Typedef {
 	genDecl, ok := node.(*ast.GenDecl)
-	if ok {
-		// Continue parsing to see if it a struct
-		mTypedef := extractSpecsForTypedef(genDecl.Specs)
-		if mTypedef != nil {
-			mTypedef.DocLines = extractComments(genDecl.Doc)
-			return mTypedef
-		}
+	if !ok || genDecl.Tok != token.TYPE {
+		return nil
 	}
-	return nil
-}
-
-func extractGenDeclForEnum(node ast.Node) *model.Enum {
-	genDecl, ok := node.(*ast.GenDecl)
-	if ok {
-		// Continue parsing to see if it is an enum
-		// Docs live in the related typedef
-		return extractSpecsForEnum(genDecl.Specs)
+	mTypedefs := []model.Typedef{}
+	for _, spec := range genDecl.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if ok {
+			mTypedef := extractSpecForTypedef(typeSpec, imports, fileSet)
+			// Within a type-block, each type has its own docline
+			mTypedef.DocLines = extractComments(typeSpec.Doc)
+			mTypedef.DocPositions = extractCommentPositions(typeSpec.Doc, fileSet)
+			if typeSpec.Doc == nil {
+				mTypedef.DocLines = extractComments(genDecl.Doc)
+				mTypedef.DocPositions = extractCommentPositions(genDecl.Doc, fileSet)
+			}
+			mTypedefs = append(mTypedefs, mTypedef)
+		}
 	}
-	return nil
+	return mTypedefs
 }
 
-func extractGenDecForInterface(node ast.Node, imports map[string]string) *model.Interface {
+func extractGenDecForInterface(node ast.Node, imports map[string]string, fileSet *token.FileSet) *model.Interface {
 	genDecl, ok := node.(*ast.GenDecl)
 	if ok {
 		// Continue parsing to see if it an interface
-		mInterface := extractSpecsForInterface(genDecl.Specs, imports)
+		mInterface := extractSpecsForInterface(genDecl.Specs, imports, fileSet)
 		if mInterface != nil {
 			// Docline of interface (that could contain annotations) appear far before the details of the struct
 			mInterface.DocLines = extractComments(genDecl.Doc)
@@ -366,15 +767,18 @@ func extractGenDecForInterface(node ast.Node, imports map[string]string) *model.
 	return nil
 }
 
-func extractSpecsForStruct(specs []ast.Spec, imports map[string]string) *model.Struct {
+func extractSpecsForStruct(specs []ast.Spec, imports map[string]string, fileSet *token.FileSet) *model.Struct {
 	if len(specs) >= 1 {
 		typeSpec, ok := specs[0].(*ast.TypeSpec)
 		if ok {
 			structType, ok := typeSpec.Type.(*ast.StructType)
 			if ok {
+				typeParams := extractTypeParams(typeSpec.TypeParams)
 				return &model.Struct{
-					Name:   typeSpec.Name.Name,
-					Fields: extractFieldList(structType.Fields, imports),
+					Position:   extractPosition(typeSpec.Name.Pos(), fileSet),
+					Name:       typeSpec.Name.Name,
+					TypeParams: typeParams,
+					Fields:     markTypeParamFields(extractStructFields(structType.Fields, imports, fileSet), typeParams),
 				}
 			}
 		}
@@ -382,62 +786,155 @@ func extractSpecsForStruct(specs []ast.Spec, imports map[string]string) *model.S
 	return nil
 }
 
-func extractSpecsForEnum(specs []ast.Spec) *model.Enum {
-	typeName, ok := extractEnumTypeName(specs)
-	if ok {
-		mEnum := model.Enum{
-			Name:         typeName,
-			EnumLiterals: []model.EnumLiteral{},
+// extractGenDeclForConstants evaluates a const-block: constants of a named type become literals of the enum of that type,
+// the others become plain constants
+func extractGenDeclForConstants(genDecl *ast.GenDecl, info *types.Info, fileSet *token.FileSet) ([]model.Enum, []model.Constant) {
+	mEnums := []model.Enum{}
+	mConstants := []model.Constant{}
+	typeName := ""
+	for _, spec := range genDecl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
 		}
-		for _, spec := range specs {
-			valueSpec, ok := spec.(*ast.ValueSpec)
-			if ok {
-				enumLiteral := model.EnumLiteral{
-					Name: valueSpec.Names[0].Name,
-				}
-				for _, value := range valueSpec.Values {
-					basicLit, ok := value.(*ast.BasicLit)
-					if ok {
-						enumLiteral.Value = strings.Trim(basicLit.Value, "\"")
-						break
-					}
-				}
-				mEnum.EnumLiterals = append(mEnum.EnumLiterals, enumLiteral)
+		// A spec without type and values repeats the previous one: example: iota-based enums
+		if valueSpec.Type != nil {
+			typeName = types.ExprString(valueSpec.Type)
+		} else if len(valueSpec.Values) > 0 {
+			typeName = ""
+		}
+		docLines := extractValueSpecDocLines(genDecl, valueSpec)
+		for _, name := range valueSpec.Names {
+			if name.Name == "_" {
+				continue
+			}
+			value := extractConstantValue(info, name)
+			if !isEnumType(typeName) {
+				mConstants = append(mConstants, model.Constant{
+					Position:     extractPosition(name.Pos(), fileSet),
+					DocLines:     docLines,
+					Name:         name.Name,
+					TypeName:     typeName,
+					Value:        value,
+					CommentLines: extractComments(valueSpec.Comment),
+				})
+				continue
+			}
+			if len(mEnums) == 0 || mEnums[len(mEnums)-1].Name != typeName {
+				mEnums = append(mEnums, model.Enum{
+					Position:     extractPosition(valueSpec.Pos(), fileSet),
+					Name:         typeName,
+					EnumLiterals: []model.EnumLiteral{},
+				})
 			}
+			mEnum := &mEnums[len(mEnums)-1]
+			mEnum.EnumLiterals = append(mEnum.EnumLiterals, model.EnumLiteral{
+				Position:     extractPosition(name.Pos(), fileSet),
+				DocLines:     docLines,
+				Name:         name.Name,
+				Value:        value,
+				CommentLines: extractComments(valueSpec.Comment),
+			})
 		}
-		return &mEnum
 	}
-	return nil
+	return mEnums, mConstants
 }
 
-func extractEnumTypeName(specs []ast.Spec) (string, bool) {
-	for _, spec := range specs {
+func extractGenDeclForVariables(genDecl *ast.GenDecl, info *types.Info, fileSet *token.FileSet) []model.Variable {
+	mVariables := []model.Variable{}
+	for _, spec := range genDecl.Specs {
 		valueSpec, ok := spec.(*ast.ValueSpec)
-		if ok {
-			if valueSpec.Type != nil {
-				for _, name := range valueSpec.Names {
-					ident, ok := valueSpec.Type.(*ast.Ident)
-					if ok {
-						if name.Obj.Kind == ast.Con {
-							return ident.Name, true
-						}
-					}
-				}
+		if !ok {
+			continue
+		}
+		typeName := ""
+		if valueSpec.Type != nil {
+			typeName = types.ExprString(valueSpec.Type)
+		}
+		for idx, name := range valueSpec.Names {
+			if name.Name == "_" {
+				continue
+			}
+			mVariable := model.Variable{
+				Position:     extractPosition(name.Pos(), fileSet),
+				DocLines:     extractValueSpecDocLines(genDecl, valueSpec),
+				Name:         name.Name,
+				TypeName:     typeName,
+				CommentLines: extractComments(valueSpec.Comment),
+			}
+			if len(valueSpec.Values) == len(valueSpec.Names) {
+				mVariable.Value = extractExpressionValue(info, valueSpec.Values[idx])
 			}
+			mVariables = append(mVariables, mVariable)
 		}
 	}
-	return "", false
+	return mVariables
+}
+
+// isEnumType tells if constants of this type are literals of an enum: named types of the package itself
+func isEnumType(typeName string) bool {
+	if typeName == "" || strings.Contains(typeName, ".") {
+		return false
+	}
+	_, isPredeclared := types.Universe.Lookup(typeName).(*types.TypeName)
+	return !isPredeclared
 }
 
-func extractSpecsForInterface(specs []ast.Spec, imports map[string]string) *model.Interface {
+func extractValueSpecDocLines(genDecl *ast.GenDecl, valueSpec *ast.ValueSpec) []string {
+	if valueSpec.Doc == nil && !genDecl.Lparen.IsValid() {
+		// Docs of an ungrouped declaration live in the declaration: example: const X = 1
+		return extractComments(genDecl.Doc)
+	}
+	return extractComments(valueSpec.Doc)
+}
+
+func extractConstantValue(info *types.Info, name *ast.Ident) string {
+	if info != nil {
+		obj, ok := info.Defs[name].(*types.Const)
+		if ok {
+			return formatConstantValue(obj.Val())
+		}
+	}
+	return ""
+}
+
+func extractExpressionValue(info *types.Info, expr ast.Expr) string {
+	if info != nil {
+		typeAndValue, ok := info.Types[expr]
+		if ok {
+			return formatConstantValue(typeAndValue.Value)
+		}
+	}
+	return ""
+}
+
+func formatConstantValue(value constant.Value) string {
+	if value == nil || value.Kind() == constant.Unknown {
+		return ""
+	}
+	if value.Kind() == constant.String {
+		return constant.StringVal(value)
+	}
+	return value.ExactString()
+}
+
+func extractSpecsForInterface(specs []ast.Spec, imports map[string]string, fileSet *token.FileSet) *model.Interface {
 	if len(specs) >= 1 {
 		typeSpec, ok := specs[0].(*ast.TypeSpec)
 		if ok {
 			interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
 			if ok {
+				typeParams := extractTypeParams(typeSpec.TypeParams)
+				methods := extractInterfaceMethods(interfaceType.Methods, imports, fileSet)
+				for idx := range methods {
+					markTypeParamFields(methods[idx].InputArgs, typeParams)
+					markTypeParamFields(methods[idx].OutputArgs, typeParams)
+				}
 				return &model.Interface{
-					Name:    typeSpec.Name.Name,
-					Methods: extractInterfaceMethods(interfaceType.Methods, imports),
+					Position:   extractPosition(typeSpec.Name.Pos(), fileSet),
+					Name:       typeSpec.Name.Name,
+					TypeParams: typeParams,
+					Methods:    methods,
 				}
 			}
 		}
@@ -445,6 +942,15 @@ func extractSpecsForInterface(specs []ast.Spec, imports map[string]string) *mode
 	return nil
 }
 
+func toFirstUpper(in string) string {
+	a := []rune(in)
+	if len(a) == 0 {
+		return in
+	}
+	a[0] = unicode.ToUpper(a[0])
+	return string(a)
+}
+
 func extractPackageName(node ast.Node) (string, bool) {
 	file, ok := node.(*ast.File)
 	if ok {
@@ -455,51 +961,100 @@ func extractPackageName(node ast.Node) (string, bool) {
 	return "", ok
 }
 
-func extractOperation(node ast.Node, imports map[string]string) *model.Operation {
+// parseAsPackageDoc records the package-doc of the file, like in doc.go
+func (v *astVisitor) parseAsPackageDoc(node ast.Node) {
+	file, ok := node.(*ast.File)
+	if !ok || file.Doc == nil || file.Name == nil {
+		return
+	}
+	v.Packages = append(v.Packages, model.Package{
+		Name:         file.Name.Name,
+		Filename:     v.CurrentFilename,
+		Position:     extractPosition(file.Name.Pos(), v.FileSet),
+		DocLines:     extractComments(file.Doc),
+		DocPositions: extractCommentPositions(file.Doc, v.FileSet),
+	})
+}
+
+func extractOperation(node ast.Node, imports map[string]string, fileSet *token.FileSet) *model.Operation {
 	funcDecl, ok := node.(*ast.FuncDecl)
 	if ok {
 		mOperation := model.Operation{
-			DocLines: extractComments(funcDecl.Doc),
+			DocLines:     extractComments(funcDecl.Doc),
+			DocPositions: extractCommentPositions(funcDecl.Doc, fileSet),
 		}
 
 		if funcDecl.Recv != nil {
-			fields := extractFieldList(funcDecl.Recv, imports)
+			fields := extractFieldList(funcDecl.Recv, imports, fileSet)
 			if len(fields) >= 1 {
 				mOperation.RelatedStruct = &(fields[0])
+				mOperation.ReceiverKind = extractReceiverKind(funcDecl.Recv.List[0])
+
+				// A method on a generic type refers to its type-parameters via the receiver: func (p *Page[T]) ...
+				typeName, typeParams, ok := extractGenericReceiver(funcDecl.Recv.List[0])
+				if ok {
+					mOperation.RelatedStruct.TypeName = typeName
+					mOperation.TypeParams = typeParams
+				}
 			}
 		}
 
 		if funcDecl.Name != nil {
+			mOperation.Position = extractPosition(funcDecl.Name.Pos(), fileSet)
 			mOperation.Name = funcDecl.Name.Name
+			mOperation.IsExported = funcDecl.Name.IsExported()
 		}
 
+		mOperation.TypeParams = append(mOperation.TypeParams, extractTypeParams(funcDecl.Type.TypeParams)...)
+
 		if funcDecl.Type.Params != nil {
-			mOperation.InputArgs = extractFieldList(funcDecl.Type.Params, imports)
+			mOperation.InputArgs = markTypeParamFields(extractFieldList(funcDecl.Type.Params, imports, fileSet), mOperation.TypeParams)
 		}
 
 		if funcDecl.Type.Results != nil {
-			mOperation.OutputArgs = extractFieldList(funcDecl.Type.Results, imports)
+			mOperation.OutputArgs = markTypeParamFields(extractFieldList(funcDecl.Type.Results, imports, fileSet), mOperation.TypeParams)
 		}
 		return &mOperation
 	}
 	return nil
 }
 
-func extractSpecsForTypedef(specs []ast.Spec) *model.Typedef {
-	if len(specs) >= 1 {
-		typeSpec, ok := specs[0].(*ast.TypeSpec)
-		if ok {
-			mTypedef := model.Typedef{
-				Name: typeSpec.Name.Name,
-			}
-			ident, ok := typeSpec.Type.(*ast.Ident)
-			if ok {
-				mTypedef.Type = ident.Name
-			}
-			return &mTypedef
-		}
+func extractReceiverKind(receiver *ast.Field) string {
+	if _, ok := receiver.Type.(*ast.StarExpr); ok {
+		return model.ReceiverPointer
+	}
+	return model.ReceiverValue
+}
+
+func extractSpecForTypedef(typeSpec *ast.TypeSpec, imports map[string]string, fileSet *token.FileSet) model.Typedef {
+	mTypedef := model.Typedef{
+		Position:   extractPosition(typeSpec.Name.Pos(), fileSet),
+		Name:       typeSpec.Name.Name,
+		TypeParams: extractTypeParams(typeSpec.TypeParams),
+		IsAlias:    typeSpec.Assign.IsValid(),
+		Definition: extractType(typeSpec.Type, imports, fileSet),
+	}
+	switch typeSpec.Type.(type) {
+	case *ast.StructType:
+		mTypedef.Underlying = model.KindStruct
+	case *ast.InterfaceType:
+		mTypedef.Underlying = model.KindInterface
+	default:
+		mTypedef.Type = types.ExprString(typeSpec.Type)
+	}
+	return mTypedef
+}
+
+func extractPosition(pos token.Pos, fileSet *token.FileSet) model.Position {
+	if fileSet == nil || !pos.IsValid() {
+		return model.Position{}
+	}
+	position := fileSet.Position(pos)
+	return model.Position{
+		Filename: position.Filename,
+		Line:     position.Line,
+		Column:   position.Column,
 	}
-	return nil
 }
 
 func extractComments(commentGroup *ast.CommentGroup) []string {
@@ -512,6 +1067,81 @@ func extractComments(commentGroup *ast.CommentGroup) []string {
 	return lines
 }
 
+// extractCommentPositions returns the positions of the comments, in the order of extractComments
+func extractCommentPositions(commentGroup *ast.CommentGroup, fileSet *token.FileSet) []model.Position {
+	positions := []model.Position{}
+	if commentGroup != nil {
+		for _, comment := range commentGroup.List {
+			positions = append(positions, extractPosition(comment.Slash, fileSet))
+		}
+	}
+	return positions
+}
+
+func extractTypeParams(fieldList *ast.FieldList) []model.TypeParam {
+	typeParams := []model.TypeParam{}
+	if fieldList != nil {
+		for _, field := range fieldList.List {
+			// A single constraint can apply to multiple: example: [K, V comparable]
+			for _, name := range field.Names {
+				typeParams = append(typeParams, model.TypeParam{
+					Name:       name.Name,
+					Constraint: types.ExprString(field.Type),
+				})
+			}
+		}
+	}
+	if len(typeParams) == 0 {
+		return nil
+	}
+	return typeParams
+}
+
+func markTypeParamFields(mFields []model.Field, typeParams []model.TypeParam) []model.Field {
+	for idx := range mFields {
+		for _, typeParam := range typeParams {
+			if mFields[idx].TypeName == typeParam.Name {
+				mFields[idx].IsTypeParam = true
+				break
+			}
+		}
+	}
+	return mFields
+}
+
+func extractGenericReceiver(field *ast.Field) (string, []model.TypeParam, bool) {
+	expr := field.Type
+	starExpr, ok := expr.(*ast.StarExpr)
+	if ok {
+		expr = starExpr.X
+	}
+	genericType, typeArgs, ok := extractGenericType(expr)
+	if !ok {
+		return "", nil, false
+	}
+	ident, ok := genericType.(*ast.Ident)
+	if !ok {
+		return "", nil, false
+	}
+	typeParams := []model.TypeParam{}
+	for _, typeArg := range typeArgs {
+		typeParams = append(typeParams, model.TypeParam{
+			Name: types.ExprString(typeArg),
+		})
+	}
+	return ident.Name, typeParams, true
+}
+
+func extractGenericType(expr ast.Expr) (ast.Expr, []ast.Expr, bool) {
+	switch genericExpr := expr.(type) {
+	case *ast.IndexExpr:
+		return genericExpr.X, []ast.Expr{genericExpr.Index}, true
+	case *ast.IndexListExpr:
+		return genericExpr.X, genericExpr.Indices, true
+	}
+	return nil, nil, false
+}
+
 func extractTag(basicLit *ast.BasicLit) string {
 	if basicLit != nil {
 		return basicLit.Value
@@ -519,27 +1149,119 @@ func extractTag(basicLit *ast.BasicLit) string {
 	return ""
 }
 
-func extractFieldList(fieldList *ast.FieldList, imports map[string]string) []model.Field {
+func extractTags(basicLit *ast.BasicLit) []model.Tag {
+	if basicLit != nil {
+		tag, err := strconv.Unquote(basicLit.Value)
+		if err == nil {
+			return parseTags(tag)
+		}
+	}
+	return nil
+}
+
+// parseTags decodes a tag like `json:"name,omitempty" db:"name"` using the same rules as reflect.StructTag, but keeps the order
+func parseTags(tag string) []model.Tag {
+	mTags := []model.Tag{}
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon: a space, a quote or a control character is a syntax error
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quotedValue := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(quotedValue)
+		if err != nil {
+			break
+		}
+		parts := strings.Split(value, ",")
+		mTag := model.Tag{
+			Key:  key,
+			Name: parts[0],
+		}
+		if len(parts) > 1 {
+			mTag.Options = parts[1:]
+		}
+		mTags = append(mTags, mTag)
+	}
+	return mTags
+}
+
+func extractFieldList(fieldList *ast.FieldList, imports map[string]string, fileSet *token.FileSet) []model.Field {
 	mFields := []model.Field{}
 	if fieldList != nil {
 		for _, field := range fieldList.List {
-			mFields = append(mFields, extractFields(field, imports)...)
+			mFields = append(mFields, extractFields(field, imports, fileSet)...)
 		}
 	}
 	return mFields
 }
 
-func extractInterfaceMethods(fieldList *ast.FieldList, imports map[string]string) []model.Operation {
+func extractStructFields(fieldList *ast.FieldList, imports map[string]string, fileSet *token.FileSet) []model.Field {
+	mFields := []model.Field{}
+	if fieldList != nil {
+		for _, field := range fieldList.List {
+			fields := extractFields(field, imports, fileSet)
+			if len(field.Names) == 0 {
+				// An embedded field is named after its type: example: *pkg.Base -> Base
+				for idx := range fields {
+					fields[idx].IsEmbedded = true
+					fields[idx].Name = embeddedFieldName(fields[idx].TypeName)
+				}
+			}
+			mFields = append(mFields, fields...)
+		}
+	}
+	return mFields
+}
+
+func embeddedFieldName(typeName string) string {
+	typeName = strings.SplitN(typeName, "[", 2)[0]
+	parts := strings.Split(typeName, ".")
+	return parts[len(parts)-1]
+}
+
+func extractInterfaceMethods(fieldList *ast.FieldList, imports map[string]string, fileSet *token.FileSet) []model.Operation {
 	methods := []model.Operation{}
 	for _, field := range fieldList.List {
 		if len(field.Names) > 0 {
 			funcType, ok := field.Type.(*ast.FuncType)
 			if ok {
 				methods = append(methods, model.Operation{
-					DocLines:   extractComments(field.Doc),
-					Name:       field.Names[0].Name,
-					InputArgs:  extractFieldList(funcType.Params, imports),
-					OutputArgs: extractFieldList(funcType.Results, imports),
+					Position:     extractPosition(field.Names[0].Pos(), fileSet),
+					DocLines:     extractComments(field.Doc),
+					DocPositions: extractCommentPositions(field.Doc, fileSet),
+					Name:         field.Names[0].Name,
+					InputArgs:    extractFieldList(funcType.Params, imports, fileSet),
+					OutputArgs:   extractFieldList(funcType.Results, imports, fileSet),
 				})
 			}
 		}
@@ -547,19 +1269,20 @@ func extractInterfaceMethods(fieldList *ast.FieldList, imports map[string]string
 	return methods
 }
 
-func extractFields(field *ast.Field, imports map[string]string) []model.Field {
+func extractFields(field *ast.Field, imports map[string]string, fileSet *token.FileSet) []model.Field {
 	mFields := []model.Field{}
 	if field != nil {
 		if len(field.Names) == 0 {
-			f, ok := extractField(field, imports)
+			f, ok := extractField(field, imports, fileSet)
 			if ok {
 				mFields = append(mFields, f)
 			}
 		} else {
 			// A single field can refer to multiple: example: x,y int -> x int, y int
 			for _, name := range field.Names {
-				field, ok := extractField(field, imports)
+				field, ok := extractField(field, imports, fileSet)
 				if ok {
+					field.Position = extractPosition(name.Pos(), fileSet)
 					field.Name = name.Name
 					mFields = append(mFields, field)
 				}
@@ -569,11 +1292,26 @@ func extractFields(field *ast.Field, imports map[string]string) []model.Field {
 	return mFields
 }
 
-func extractField(field *ast.Field, imports map[string]string) (model.Field, bool) {
+func extractField(field *ast.Field, imports map[string]string, fileSet *token.FileSet) (model.Field, bool) {
+	ellipsis, ok := field.Type.(*ast.Ellipsis)
+	if ok {
+		// Within the operation a variadic parameter is a slice: example: opts ...Option -> opts []Option
+		sliceField := *field
+		sliceField.Type = &ast.ArrayType{Lbrack: ellipsis.Ellipsis, Elt: ellipsis.Elt}
+		mField, ok := extractField(&sliceField, imports, fileSet)
+		mField.IsVariadic = true
+		return mField, ok
+	}
+
 	mField := model.Field{
-		DocLines:     extractComments(field.Doc),
-		CommentLines: extractComments(field.Comment),
-		Tag:          extractTag(field.Tag),
+		Position:         extractPosition(field.Pos(), fileSet),
+		DocLines:         extractComments(field.Doc),
+		DocPositions:     extractCommentPositions(field.Doc, fileSet),
+		CommentLines:     extractComments(field.Comment),
+		CommentPositions: extractCommentPositions(field.Comment, fileSet),
+		Tag:              extractTag(field.Tag),
+		Tags:             extractTags(field.Tag),
+		Type:             extractType(field.Type, imports, fileSet),
 	}
 
 	if extractSliceField(field, &mField, imports) {
@@ -596,7 +1334,11 @@ func extractField(field *ast.Field, imports map[string]string) (model.Field, boo
 		return mField, true
 	}
 
-	log.Printf("*** Could not understand field '%+v'", field.Type)
+	if extractOtherField(field, &mField, imports) {
+		return mField, true
+	}
+
+	diagnostics.Warnf(mField.Position, "Could not understand field of type %s", types.ExprString(field.Type))
 
 	return mField, false
 }
@@ -727,7 +1469,6 @@ func extractSelectorField(field *ast.Field, mField *model.Field, imports map[str
 	if ok {
 		ident, ok := selectorExpr.X.(*ast.Ident)
 		if ok {
-			mField.Name = ident.Name
 			mField.TypeName = fmt.Sprintf("%s.%s", ident.Name, selectorExpr.Sel.Name)
 			mField.PackageName = imports[ident.Name]
 			return true
@@ -735,3 +1476,140 @@ func extractSelectorField(field *ast.Field, mField *model.Field, imports map[str
 	}
 	return false
 }
+
+func extractOtherField(field *ast.Field, mField *model.Field, imports map[string]string) bool {
+	if mField.Type == nil {
+		return false
+	}
+
+	// Describe the outer slice and pointer the legacy way: the full type is available in mField.Type
+	isSlice := false
+	isPointer := false
+
+	expr := field.Type
+	arrayType, ok := expr.(*ast.ArrayType)
+	if ok && arrayType.Len == nil {
+		isSlice = true
+		expr = arrayType.Elt
+	}
+	starExpr, ok := expr.(*ast.StarExpr)
+	if ok {
+		isPointer = true
+		expr = starExpr.X
+	}
+
+	mField.TypeName = types.ExprString(expr)
+	mField.IsSlice = isSlice
+	mField.IsPointer = isPointer
+
+	genericType, _, ok := extractGenericType(expr)
+	if ok {
+		expr = genericType
+	}
+	selectorExpr, ok := expr.(*ast.SelectorExpr)
+	if ok {
+		ident, ok := selectorExpr.X.(*ast.Ident)
+		if ok {
+			mField.PackageName = imports[ident.Name]
+		}
+	}
+	return true
+}
+
+func extractType(expr ast.Expr, imports map[string]string, fileSet *token.FileSet) *model.Type {
+	switch typ := expr.(type) {
+	case *ast.Ident:
+		return &model.Type{
+			Kind: model.KindNamed,
+			Name: typ.Name,
+		}
+	case *ast.SelectorExpr:
+		ident, ok := typ.X.(*ast.Ident)
+		if ok {
+			return &model.Type{
+				Kind:        model.KindNamed,
+				Name:        typ.Sel.Name,
+				Package:     ident.Name,
+				PackagePath: imports[ident.Name],
+			}
+		}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		genericType, typeArgs, _ := extractGenericType(typ)
+		mType := extractType(genericType, imports, fileSet)
+		if mType != nil {
+			for _, typeArg := range typeArgs {
+				mTypeArg := extractType(typeArg, imports, fileSet)
+				if mTypeArg == nil {
+					return nil
+				}
+				mType.TypeArgs = append(mType.TypeArgs, *mTypeArg)
+			}
+		}
+		return mType
+	case *ast.ParenExpr:
+		return extractType(typ.X, imports, fileSet)
+	case *ast.StarExpr:
+		return extractElemType(model.KindPointer, typ.X, imports, fileSet)
+	case *ast.Ellipsis:
+		// variadic parameter: behaves as a slice, the field is marked as variadic
+		return extractElemType(model.KindSlice, typ.Elt, imports, fileSet)
+	case *ast.ArrayType:
+		if typ.Len == nil {
+			return extractElemType(model.KindSlice, typ.Elt, imports, fileSet)
+		}
+		mType := extractElemType(model.KindArray, typ.Elt, imports, fileSet)
+		if mType != nil {
+			mType.Len = types.ExprString(typ.Len)
+		}
+		return mType
+	case *ast.ChanType:
+		mType := extractElemType(model.KindChan, typ.Value, imports, fileSet)
+		if mType != nil {
+			switch typ.Dir {
+			case ast.SEND:
+				mType.ChanDir = "send"
+			case ast.RECV:
+				mType.ChanDir = "recv"
+			}
+		}
+		return mType
+	case *ast.MapType:
+		key := extractType(typ.Key, imports, fileSet)
+		value := extractType(typ.Value, imports, fileSet)
+		if key != nil && value != nil {
+			return &model.Type{
+				Kind:  model.KindMap,
+				Key:   key,
+				Value: value,
+			}
+		}
+	case *ast.FuncType:
+		return &model.Type{
+			Kind:    model.KindFunc,
+			Params:  extractFieldList(typ.Params, imports, fileSet),
+			Results: extractFieldList(typ.Results, imports, fileSet),
+		}
+	case *ast.StructType:
+		return &model.Type{
+			Kind:   model.KindStruct,
+			Fields: extractStructFields(typ.Fields, imports, fileSet),
+		}
+	case *ast.InterfaceType:
+		return &model.Type{
+			Kind:    model.KindInterface,
+			Methods: extractInterfaceMethods(typ.Methods, imports, fileSet),
+		}
+	}
+	return nil
+}
+
+func extractElemType(kind string, elem ast.Expr, imports map[string]string, fileSet *token.FileSet) *model.Type {
+	mElem := extractType(elem, imports, fileSet)
+	if mElem == nil {
+		return nil
+	}
+	return &model.Type{
+		Kind: kind,
+		Elem: mElem,
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/annotation"
+	"github.com/MarcGrol/golangAnnotations/generator/rest/restAnnotation"
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachAnnotations(t *testing.T) {
+	annotation.RegisterDescriptors("rest", restAnnotation.Get())
+
+	parsedSources, err := New().ParseSourceDir("annotations", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+
+	service := parsedSources.Structs[0]
+	assert.Equal(t, restAnnotation.TypeRestService, service.Annotations[0].Name)
+	assert.Equal(t, restAnnotation.TypeRestOperation, service.Operations[0].Annotations[0].Name)
+
+	// the annotations survive a json round-trip, like to a plugin
+	marshalled, err := json.Marshal(parsedSources)
+	assert.NoError(t, err)
+	roundTripped := model.ParsedSources{}
+	err = json.Unmarshal(marshalled, &roundTripped)
+	assert.NoError(t, err)
+	registry := annotation.NewRegistry(restAnnotation.Get())
+	ann, ok := registry.FindAnnotation(roundTripped.Structs[0].Annotations, roundTripped.Structs[0].DocLines, restAnnotation.TypeRestService)
+	assert.True(t, ok)
+	assert.Equal(t, "/api", ann.Attributes[restAnnotation.ParamPath])
+}
+
+func TestMisplacedAnnotations(t *testing.T) {
+	annotation.RegisterDescriptors("rest", restAnnotation.Get())
+
+	parsedSources, err := New().ParseSourceDir("annotations", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+
+	registry := annotation.NewRegistry(annotation.RegisteredDescriptors())
+	problems := annotation.ValidateAnnotationRules(registry, parsedSources)
+	assert.Equal(t, 1, len(problems))
+	assert.Equal(t, "annotations/annotations.go:6:2: Misplaced annotation @RestService: it can be used on structs only, not on fields", problems[0].Error())
+}
+
+func TestAnnotationProblemLines(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("annotationLines", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+
+	// the lines of the comments themselves: the doc of a type-block is not directly above the struct
+	problems := annotation.ValidateAnnotations(annotation.NewRegistry(restAnnotation.Get()), parsedSources)
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	assert.Equal(t, []string{
+		"annotationLines/annotationLines.go:3: Unknown parameter protectd of annotation @RestService: did you mean protected?",
+		"annotationLines/annotationLines.go:6: Unknown parameter pth of annotation @RestService: did you mean path?",
+		"annotationLines/annotationLines.go:6: Missing parameter path of annotation @RestService",
+		`annotationLines/annotationLines.go:10: Invalid value for parameter form of annotation @RestOperation: string-value "maybe" is not a bool`,
+	}, messages)
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConstraintsDefaultTags(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./buildtags", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Common", "Free"}, structNames(parsedSources.Structs))
+}
+
+func TestBuildConstraintsWithTags(t *testing.T) {
+	parsedSources, err := New("pro").ParseSourceDir("./buildtags", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Common", "Pro"}, structNames(parsedSources.Structs))
+
+	parsedSources, err = New("pro", "appengine").ParseSourceDir("./buildtags", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Common"}, structNames(parsedSources.Structs))
+}
+
+func TestBuildConstraintsTypeChecked(t *testing.T) {
+	parsedSources, err := NewTypeChecked("pro").ParseSourceDir("./buildtags", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Common", "Pro"}, structNames(parsedSources.Structs))
+}
+
+func structNames(structs []model.Struct) []string {
+	names := []string{}
+	for _, s := range structs {
+		names = append(names, s.Name)
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedParserReusesEntries(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	sourceDir, err := ioutil.TempDir("", "golangAnnotations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sourceDir)
+	for _, filename := range []string{"aStruct.go", "example.go", "otherExample.go"} {
+		content, err := ioutil.ReadFile(filepath.Join("structs", filename))
+		assert.NoError(t, err)
+		err = ioutil.WriteFile(filepath.Join(sourceDir, filename), content, 0644)
+		assert.NoError(t, err)
+	}
+
+	uncached, err := New().ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+
+	cached, err := NewCached(cacheDir, "test").ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, marshal(t, uncached), marshal(t, cached))
+
+	// An entry per file
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(entries))
+
+	// Prove the entry of example.go is used by tampering with it
+	tampered := false
+	for _, entry := range entries {
+		content, err := ioutil.ReadFile(entry)
+		assert.NoError(t, err)
+		if strings.Contains(string(content), `"name":"Person"`) {
+			err = ioutil.WriteFile(entry, []byte(strings.Replace(string(content), `"name":"Person"`, `"name":"Cached"`, -1)), 0644)
+			assert.NoError(t, err)
+			tampered = true
+		}
+	}
+	assert.True(t, tampered)
+
+	cached, err = NewCached(cacheDir, "test").ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Contains(t, marshal(t, cached), `"name":"Cached"`)
+
+	// A change of another file does not invalidate the entry of example.go
+	err = ioutil.WriteFile(filepath.Join(sourceDir, "aStruct.go"), []byte("package structs\n\ntype YetAnotherStruct struct {\n\tY int\n\tZ int\n}\n"), 0644)
+	assert.NoError(t, err)
+	cached, err = NewCached(cacheDir, "test").ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Contains(t, marshal(t, cached), `"name":"Cached"`)
+	assert.Contains(t, marshal(t, cached), `"name":"Z"`)
+
+	// A change of the constants of the package does
+	err = ioutil.WriteFile(filepath.Join(sourceDir, "aStruct.go"), []byte("package structs\n\nconst Blue ColorType = 7\n"), 0644)
+	assert.NoError(t, err)
+	cached, err = NewCached(cacheDir, "test").ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.NotContains(t, marshal(t, cached), `"name":"Cached"`)
+	assert.Contains(t, marshal(t, cached), `"name":"Person"`)
+
+	// Another tool-version does not use the entries
+	err = ioutil.WriteFile(filepath.Join(sourceDir, "aStruct.go"), []byte("package structs\n\ntype YetAnotherStruct struct {\n\tY int\n}\n"), 0644)
+	assert.NoError(t, err)
+	cached, err = NewCached(cacheDir, "other").ParseSourceDir(sourceDir, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, marshal(t, uncached), marshal(t, cached))
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	content, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(content)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantsInDir(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./constants", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assertConstants(t, parsedSources)
+}
+
+func TestConstantsTypeChecked(t *testing.T) {
+	parsedSources, err := NewTypeChecked().ParseSourceDir("./constants", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assertConstants(t, parsedSources)
+
+	// Type-checked parsing also resolves imported constants
+	assert.Equal(t, "Timeout", parsedSources.Constants[2].Name)
+	assert.Equal(t, "5000000000", parsedSources.Constants[2].Value)
+}
+
+func assertConstants(t *testing.T, parsedSources model.ParsedSources) {
+	assert.Equal(t, 2, len(parsedSources.Enums))
+	{
+		// iota-based, skipping the blank literal and assembled from multiple const-blocks
+		e := parsedSources.Enums[0]
+		assert.Equal(t, "Weekday", e.Name)
+		assert.Equal(t, 5, len(e.EnumLiterals))
+		assert.Equal(t, "Monday", e.EnumLiterals[0].Name)
+		assert.Equal(t, "1", e.EnumLiterals[0].Value)
+		assert.Equal(t, []string{"// First day of the week"}, e.EnumLiterals[0].DocLines)
+		assert.Equal(t, 10, e.EnumLiterals[0].Position.Line)
+		assert.Equal(t, "Tuesday", e.EnumLiterals[1].Name)
+		assert.Equal(t, "2", e.EnumLiterals[1].Value)
+		assert.Equal(t, []string{"// after the weekend"}, e.EnumLiterals[1].CommentLines)
+		assert.Equal(t, "3", e.EnumLiterals[2].Value)
+		assert.Equal(t, "Thursday", e.EnumLiterals[3].Name)
+		assert.Equal(t, "4", e.EnumLiterals[3].Value)
+		assert.Equal(t, "Friday", e.EnumLiterals[4].Name)
+		assert.Equal(t, "5", e.EnumLiterals[4].Value)
+	}
+	{
+		e := parsedSources.Enums[1]
+		assert.Equal(t, "Permission", e.Name)
+		assert.Equal(t, []string{"1", "2", "4"}, []string{e.EnumLiterals[0].Value, e.EnumLiterals[1].Value, e.EnumLiterals[2].Value})
+	}
+
+	assert.Equal(t, 5, len(parsedSources.Constants))
+	{
+		c := parsedSources.Constants[0]
+		assert.Equal(t, "MaxRetries", c.Name)
+		assert.Equal(t, "", c.TypeName)
+		assert.Equal(t, "3", c.Value)
+		assert.Equal(t, []string{"// MaxRetries limits the attempts"}, c.DocLines)
+		assert.Equal(t, "constants", c.PackageName)
+		assert.Equal(t, "constants/constants.go", c.Filename)
+	}
+	assert.Equal(t, "Prefix", parsedSources.Constants[1].Name)
+	assert.Equal(t, "api/", parsedSources.Constants[1].Value)
+	assert.Equal(t, "KB", parsedSources.Constants[3].Name)
+	assert.Equal(t, "int", parsedSources.Constants[3].TypeName)
+	assert.Equal(t, "1024", parsedSources.Constants[3].Value)
+	assert.Equal(t, "Ratio", parsedSources.Constants[4].Name)
+	assert.Equal(t, "3/2", parsedSources.Constants[4].Value)
+
+	assert.Equal(t, 3, len(parsedSources.Variables))
+	{
+		v := parsedSources.Variables[0]
+		assert.Equal(t, "DefaultPath", v.Name)
+		assert.Equal(t, "api/v1", v.Value)
+		assert.Equal(t, []string{"// DefaultPath is where it all starts"}, v.DocLines)
+	}
+	assert.Equal(t, "counter", parsedSources.Variables[1].Name)
+	assert.Equal(t, "int", parsedSources.Variables[1].TypeName)
+	assert.Equal(t, "", parsedSources.Variables[1].Value)
+	assert.Equal(t, "started", parsedSources.Variables[2].Name)
+	assert.Equal(t, "time.Time", parsedSources.Variables[2].TypeName)
+}
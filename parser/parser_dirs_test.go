@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSourceDirsRecursive(t *testing.T) {
+	dirs, err := FindSourceDirs([]string{"./structs/..."}, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"structs", "structs/substruct"}, dirs)
+}
+
+func TestFindSourceDirsExplicit(t *testing.T) {
+	dirs, err := FindSourceDirs([]string{"./enums", "structs/", "enums"}, "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"enums", "structs"}, dirs)
+}
+
+func TestFindSourceDirsNoMatchingSources(t *testing.T) {
+	dirs, err := FindSourceDirs([]string{"./structs/..."}, "^.*.txt$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, dirs)
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedInDir(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./embedded", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 7, len(parsedSources.Structs))
+
+	{
+		s := parsedSources.Structs[1]
+		assert.Equal(t, "Base", s.Name)
+		assertField(t, model.Field{Name: "Audit", TypeName: "Audit"}, s.Fields[0])
+		assert.True(t, s.Fields[0].IsEmbedded)
+		assert.False(t, s.Fields[1].IsEmbedded)
+
+		assert.Equal(t, 2, len(s.PromotedFields))
+		assert.Equal(t, "Created", s.PromotedFields[0].Name)
+		assert.Equal(t, []string{"Audit"}, s.PromotedFields[0].PromotedVia)
+		assert.Equal(t, "Tags", s.PromotedFields[1].Name)
+		assert.Equal(t, 1, len(s.PromotedOperations))
+		assert.Equal(t, "Describe", s.PromotedOperations[0].Name)
+	}
+	{
+		s := parsedSources.Structs[3]
+		assert.Equal(t, "Document", s.Name)
+		assert.Equal(t, 4, len(s.Fields))
+		assertField(t, model.Field{Name: "Base", TypeName: "Base"}, s.Fields[0])
+		assert.True(t, s.Fields[0].IsEmbedded)
+		assertField(t, model.Field{Name: "Other", TypeName: "Other", IsPointer: true}, s.Fields[1])
+		assert.True(t, s.Fields[1].IsEmbedded)
+		assertField(t, model.Field{Name: "YetAnotherStruct", TypeName: "structs.YetAnotherStruct", IsPointer: true,
+			PackageName: "github.com/MarcGrol/golangAnnotations/parser/structs"}, s.Fields[2])
+		assert.True(t, s.Fields[2].IsEmbedded)
+		assert.False(t, s.Fields[3].IsEmbedded)
+
+		// ID is ambiguous: both Base and Other provide it at the same depth
+		names := []string{}
+		for _, f := range s.PromotedFields {
+			names = append(names, f.Name)
+		}
+		assert.Equal(t, []string{"Audit", "Labels", "Created", "Tags"}, names)
+		assert.Equal(t, []string{"Base", "Audit"}, s.PromotedFields[2].PromotedVia)
+
+		assert.Equal(t, 2, len(s.PromotedOperations))
+		assert.Equal(t, "Validate", s.PromotedOperations[0].Name)
+		assert.Equal(t, "Describe", s.PromotedOperations[1].Name)
+	}
+	{
+		s := parsedSources.Structs[6]
+		assert.Equal(t, "Diamond", s.Name)
+		names := []string{}
+		for _, f := range s.PromotedFields {
+			names = append(names, f.Name)
+		}
+		// Audit is embedded via Left and via Right: Audit, Created, Tags and Describe are ambiguous
+		assert.Equal(t, []string{"Side"}, names)
+		assert.Equal(t, 0, len(s.PromotedOperations))
+	}
+}
+
+func TestEmbeddedTypeChecked(t *testing.T) {
+	parsedSources, err := NewTypeChecked().ParseSourceDir("./typed", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+
+	s := parsedSources.Structs[1]
+	assert.Equal(t, "Document", s.Name)
+	names := []string{}
+	for _, f := range s.PromotedFields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"Created", "Tags", "Y"}, names)
+	assert.Equal(t, []string{"*YetAnotherStruct"}, s.PromotedFields[2].PromotedVia)
+}
@@ -33,8 +33,11 @@ func TestParseEnumsInFile(t *testing.T) {
 		assert.Equal(t, "// @Enum()", parsedSources.Enums[0].DocLines[0])
 		assert.Equal(t, "ColorType", parsedSources.Enums[0].Name)
 		assert.Equal(t, "Red", parsedSources.Enums[0].EnumLiterals[0].Name)
+		assert.Equal(t, "0", parsedSources.Enums[0].EnumLiterals[0].Value)
 		assert.Equal(t, "Green", parsedSources.Enums[0].EnumLiterals[1].Name)
+		assert.Equal(t, "1", parsedSources.Enums[0].EnumLiterals[1].Value)
 		assert.Equal(t, "Blue", parsedSources.Enums[0].EnumLiterals[2].Name)
+		assert.Equal(t, "2", parsedSources.Enums[0].EnumLiterals[2].Value)
 		assert.Equal(t, "enums/enum.go", parsedSources.Enums[0].Filename)
 		assert.Equal(t, "enums", parsedSources.Enums[0].PackageName)
 
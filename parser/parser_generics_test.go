@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericsInFile(t *testing.T) {
+	parsedSources, err := parseSourceFile("generics/generics.go")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(parsedSources.Structs))
+
+	{
+		s := parsedSources.Structs[0]
+		assert.Equal(t, "Page", s.Name)
+		assert.Equal(t, []model.TypeParam{{Name: "T", Constraint: "any"}}, s.TypeParams)
+		assert.Equal(t, 3, len(s.Fields))
+		assertField(t, model.Field{Name: "Items", TypeName: "T", IsSlice: true}, s.Fields[0])
+		assert.True(t, s.Fields[0].IsTypeParam)
+		assertField(t, model.Field{Name: "Selected", TypeName: "T", IsPointer: true}, s.Fields[1])
+		assert.True(t, s.Fields[1].IsTypeParam)
+		assertField(t, model.Field{Name: "Total", TypeName: "int"}, s.Fields[2])
+		assert.False(t, s.Fields[2].IsTypeParam)
+
+		assert.Equal(t, 1, len(s.Operations))
+		assert.Equal(t, "first", s.Operations[0].Name)
+		assert.Equal(t, []model.TypeParam{{Name: "T", Constraint: "any"}}, s.Operations[0].TypeParams)
+	}
+	{
+		s := parsedSources.Structs[1]
+		assert.Equal(t, "Pair", s.Name)
+		assert.Equal(t, []model.TypeParam{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "any"}}, s.TypeParams)
+		assert.True(t, s.Fields[0].IsTypeParam)
+		assert.True(t, s.Fields[1].IsTypeParam)
+	}
+	{
+		s := parsedSources.Structs[2]
+		assert.Equal(t, "Catalog", s.Name)
+		assert.Empty(t, s.TypeParams)
+		assertField(t, model.Field{Name: "Products", TypeName: "Page[string]"}, s.Fields[0])
+		assertField(t, model.Field{Name: "Prices", TypeName: "Pair[string, int]", IsSlice: true, IsPointer: true}, s.Fields[1])
+	}
+	{
+		assert.Equal(t, 1, len(parsedSources.Interfaces))
+		i := parsedSources.Interfaces[0]
+		assert.Equal(t, "Lookup", i.Name)
+		assert.Equal(t, 2, len(i.TypeParams))
+		assert.True(t, i.Methods[0].InputArgs[0].IsTypeParam)
+		assert.True(t, i.Methods[0].OutputArgs[0].IsTypeParam)
+		assert.False(t, i.Methods[0].OutputArgs[1].IsTypeParam)
+	}
+	{
+		assert.Equal(t, 2, len(parsedSources.Operations))
+		o := parsedSources.Operations[0]
+		assert.Equal(t, "first", o.Name)
+		assertField(t, model.Field{Name: "p", TypeName: "Page", IsPointer: true}, *o.RelatedStruct)
+		assert.True(t, o.OutputArgs[0].IsTypeParam)
+
+		o = parsedSources.Operations[1]
+		assert.Equal(t, "Map", o.Name)
+		assert.Nil(t, o.RelatedStruct)
+		assert.Equal(t, []model.TypeParam{{Name: "From", Constraint: "any"}, {Name: "To", Constraint: "any"}}, o.TypeParams)
+		assertField(t, model.Field{Name: "in", TypeName: "From", IsSlice: true}, o.InputArgs[0])
+		assert.True(t, o.InputArgs[0].IsTypeParam)
+		assertField(t, model.Field{TypeName: "To", IsSlice: true}, o.OutputArgs[0])
+		assert.True(t, o.OutputArgs[0].IsTypeParam)
+	}
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImports(t *testing.T) {
+	parsedSources, err := ParseSources(map[string][]byte{
+		"shop/order.go": []byte(`package shop
+
+import (
+	"io"
+	stdctx "context"
+
+	"github.com/gofrs/uuid/v5"
+	"gopkg.in/yaml.v2"
+	_ "github.com/lib/pq"
+	. "github.com/shop/money"
+)
+
+type Order struct {
+	ID      uuid.UUID
+	Ctx     stdctx.Context
+	Body    io.Reader
+	Config  yaml.MapSlice
+	Price   Amount
+	Prices  []*Amount
+	Lines   []OrderLine
+	Name    string
+}
+`),
+		"shop/line.go": []byte(`package shop
+
+import uuid "github.com/google/uuid"
+
+type OrderLine struct {
+	ID uuid.UUID
+}
+`),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []model.Import{
+		{Filename: "shop/line.go", Position: model.Position{Filename: "shop/line.go", Line: 3, Column: 8}, Name: "uuid", Path: "github.com/google/uuid", Qualifier: "uuid"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 4, Column: 2}, Path: "io", Qualifier: "io"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 5, Column: 2}, Name: "stdctx", Path: "context", Qualifier: "stdctx"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 7, Column: 2}, Path: "github.com/gofrs/uuid/v5", Qualifier: "uuid"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 8, Column: 2}, Path: "gopkg.in/yaml.v2", Qualifier: "yaml"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 9, Column: 2}, Name: "_", Path: "github.com/lib/pq"},
+		{Filename: "shop/order.go", Position: model.Position{Filename: "shop/order.go", Line: 10, Column: 2}, Name: ".", Path: "github.com/shop/money"},
+	}, parsedSources.Imports)
+
+	packageNames := map[string]string{}
+	for _, s := range parsedSources.Structs {
+		for _, f := range s.Fields {
+			packageNames[s.Name+"."+f.Name] = f.PackageName
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"OrderLine.ID": "github.com/google/uuid",
+		"Order.ID":     "github.com/gofrs/uuid/v5",
+		"Order.Ctx":    "context",
+		"Order.Body":   "io",
+		"Order.Config": "gopkg.in/yaml.v2",
+		"Order.Price":  "github.com/shop/money",
+		"Order.Prices": "github.com/shop/money",
+		"Order.Lines":  "",
+		"Order.Name":   "",
+	}, packageNames)
+	assert.Equal(t, "github.com/shop/money", parsedSources.Structs[1].Fields[5].Type.Elem.Elem.PackagePath)
+}
+
+func TestVendorlessPackagePath(t *testing.T) {
+	assert.Equal(t, "github.com/x/y", packagePath(types.NewPackage("github.com/me/app/vendor/github.com/x/y", "y")))
+	assert.Equal(t, "github.com/x/y", packagePath(types.NewPackage("vendor/github.com/x/y", "y")))
+	assert.Equal(t, "github.com/x/y", packagePath(types.NewPackage("github.com/x/y", "y")))
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var memorySources = map[string][]byte{
+	"shop/order.go": []byte(`package shop
+
+// @JsonStruct()
+type Order struct {
+	Lines []OrderLine
+}
+
+func (o *Order) Total() int {
+	return len(o.Lines) * unitPrice
+}
+`),
+	"shop/line.go": []byte(`package shop
+
+const unitPrice = 2 * 5
+
+type OrderLine struct {
+	Name string
+}
+`),
+	"shop/pro.go": []byte(`//go:build pro
+
+package shop
+
+type Discount struct {
+}
+`),
+}
+
+func TestParseSources(t *testing.T) {
+	parsedSources, err := ParseSources(memorySources)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OrderLine", "Order"}, structNames(parsedSources.Structs))
+	assert.Equal(t, "shop/order.go", parsedSources.Structs[1].Filename)
+	assert.Equal(t, 8, parsedSources.Structs[1].Operations[0].Position.Line)
+	assert.Equal(t, "10", parsedSources.Constants[0].Value)
+
+	parsedSources, err = ParseSources(memorySources, "pro")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OrderLine", "Order", "Discount"}, structNames(parsedSources.Structs))
+}
+
+func TestParseSourcesSyntaxError(t *testing.T) {
+	_, err := ParseSources(map[string][]byte{"broken.go": []byte("package broken\n\nfunc {")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "broken.go:3:6")
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for filename, src := range memorySources {
+		fsys[filename] = &fstest.MapFile{Data: src}
+	}
+	fsys["shop/gen_order.go"] = &fstest.MapFile{Data: []byte("package shop\n\ntype Generated struct {\n}\n")}
+	fsys["shop/README.md"] = &fstest.MapFile{Data: []byte("# shop")}
+
+	parsedSources, err := ParseFS(fsys, "shop", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OrderLine", "Order"}, structNames(parsedSources.Structs))
+
+	_, err = ParseFS(fsys, "missing", "^.*.go$", "gen_.*")
+	assert.Error(t, err)
+}
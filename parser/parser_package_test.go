@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/parser/parserUtil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageDoc(t *testing.T) {
+	for _, p := range []parserUtil.Parser{New(), NewTypeChecked()} {
+		parsedSources, err := p.ParseSourceDir("./packages", "^.*.go$", "gen_.*")
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, len(parsedSources.Packages))
+		mPackage := parsedSources.Packages[0]
+		assert.Equal(t, "packages", mPackage.Name)
+		assert.Equal(t, "packages/doc.go", mPackage.Filename)
+		assert.Equal(t, 4, mPackage.Position.Line)
+		assert.Equal(t, []string{
+			"// Package packages is an example of a package with default annotations",
+			"//",
+			`// @Event( aggregate = "Tour" )`,
+		}, mPackage.DocLines)
+	}
+}
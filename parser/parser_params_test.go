@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncAndVariadicParamsInDir(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./params", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assertParams(t, parsedSources)
+}
+
+func TestFuncAndVariadicParamsTypeChecked(t *testing.T) {
+	parsedSources, err := NewTypeChecked().ParseSourceDir("./params", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assertParams(t, parsedSources)
+}
+
+func assertParams(t *testing.T, parsedSources model.ParsedSources) {
+	assert.Equal(t, 2, len(parsedSources.Operations))
+	{
+		o := parsedSources.Operations[0]
+		assert.Equal(t, "subscribe", o.Name)
+		assert.Equal(t, 3, len(o.InputArgs))
+
+		cb := o.InputArgs[1]
+		assert.Equal(t, "cb", cb.Name)
+		assert.Equal(t, "func(context.Context, ...string) error", cb.TypeName)
+		assert.Equal(t, model.KindFunc, cb.Type.Kind)
+		assert.Equal(t, 2, len(cb.Type.Params))
+		assert.True(t, cb.Type.Params[1].IsVariadic)
+		assert.Equal(t, "error", cb.Type.Results[0].TypeName)
+		assert.Equal(t, "func(context.Context, ...string) error", cb.TypeString())
+
+		opts := o.InputArgs[2]
+		assert.Equal(t, "opts", opts.Name)
+		assert.True(t, opts.IsVariadic)
+		assert.True(t, opts.IsSlice)
+		assert.Equal(t, "Option", opts.TypeName)
+		assert.Equal(t, "...Option", opts.TypeString())
+	}
+	{
+		o := parsedSources.Operations[1]
+		assert.Equal(t, "write", o.Name)
+		assert.Equal(t, 3, len(o.InputArgs))
+
+		w := o.InputArgs[0]
+		assert.Equal(t, "io.Writer", w.TypeName)
+		assert.False(t, w.IsVariadic)
+
+		f := o.InputArgs[1]
+		assert.Equal(t, model.KindInterface, f.Type.Kind)
+		assert.Equal(t, "Flush", f.Type.Methods[0].Name)
+		assert.Equal(t, "interface{Flush() error}", f.TypeString())
+
+		names := o.InputArgs[2]
+		assert.True(t, names.IsVariadic)
+		assert.True(t, names.IsPointer)
+		assert.Equal(t, "string", names.TypeName)
+		assert.Equal(t, "...*string", names.TypeString())
+	}
+}
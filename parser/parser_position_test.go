@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionsInFile(t *testing.T) {
+	parsedSources, err := parseSourceFile("structs/example.go")
+	assert.Equal(t, nil, err)
+
+	s := parsedSources.Structs[0]
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 6, Column: 6}, s.Position)
+	assert.Equal(t, "structs/example.go:6:6", s.Position.String())
+
+	// FirstName, LastName string
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 7, Column: 2}, s.Fields[0].Position)
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 7, Column: 13}, s.Fields[1].Position)
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 10, Column: 2}, s.Fields[3].Position)
+
+	typedef := parsedSources.Typedefs[1]
+	assert.Equal(t, "ColorType", typedef.Name)
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 19, Column: 6}, typedef.Position)
+
+	o := parsedSources.Operations[0]
+	assert.Equal(t, "MyFunc", o.Name)
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 27, Column: 6}, o.Position)
+	assert.Equal(t, model.Position{Filename: "structs/example.go", Line: 27, Column: 13}, o.InputArgs[0].Position)
+}
+
+func TestEnumPositionsInDir(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("enums", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+
+	e := parsedSources.Enums[1]
+	assert.Equal(t, "Profession", e.Name)
+	assert.Equal(t, model.Position{Filename: "enums/enum.go", Line: 16, Column: 2}, e.Position)
+	assert.Equal(t, model.Position{Filename: "enums/enum.go", Line: 17, Column: 2}, e.EnumLiterals[1].Position)
+}
+
+func TestPositionsTypeChecked(t *testing.T) {
+	parsedSources, err := NewTypeChecked().ParseSourceDir("./typed", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+
+	s := parsedSources.Structs[0]
+	assert.Equal(t, "Base", s.Name)
+	assert.True(t, s.Position.IsValid())
+	assert.True(t, s.Fields[0].Position.IsValid())
+}
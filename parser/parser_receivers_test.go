@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiversAndConstructors(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./receivers", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(parsedSources.Structs))
+
+	operations := map[string]model.Operation{}
+	for _, o := range parsedSources.Operations {
+		operations[o.Name] = o
+	}
+	assert.Equal(t, model.ReceiverPointer, operations["Increment"].ReceiverKind)
+	assert.True(t, operations["Increment"].IsExported)
+	assert.Equal(t, model.ReceiverValue, operations["count2"].ReceiverKind)
+	assert.True(t, operations["count2"].HasValueReceiver())
+	assert.False(t, operations["count2"].IsExported)
+	assert.Equal(t, "", operations["helper"].ReceiverKind)
+	assert.False(t, operations["helper"].IsMethod())
+
+	{
+		s := parsedSources.Structs[0]
+		assert.Equal(t, "Service", s.Name)
+		assert.Equal(t, 2, len(s.Operations))
+		assert.Equal(t, 1, len(s.Constructors))
+		assert.Equal(t, "NewService", s.Constructor().Name)
+		assert.Equal(t, []string{"// NewService creates a service"}, s.Constructor().DocLines)
+		assert.False(t, s.Constructor().ReturnsError())
+	}
+	{
+		s := parsedSources.Structs[1]
+		assert.Equal(t, "Settings", s.Name)
+		assert.Equal(t, "NewSettings", s.Constructor().Name)
+		assert.True(t, s.Constructor().ReturnsError())
+	}
+}
@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []model.Tag{
+		{Key: "json", Name: "name", Options: []string{"omitempty"}},
+		{Key: "db", Name: "the name"},
+		{Key: "xml", Name: "", Options: []string{"attr"}},
+	}, parseTags(`json:"name,omitempty" db:"the name"  xml:",attr"`))
+
+	assert.Equal(t, []model.Tag{{Key: "json", Name: "-"}}, parseTags(`json:"-"`))
+	assert.Equal(t, []model.Tag{{Key: "escaped", Name: `a"b`}}, parseTags(`escaped:"a\"b"`))
+
+	// like reflect.StructTag: decoding stops at a syntax error
+	assert.Equal(t, []model.Tag{{Key: "json", Name: "ok"}}, parseTags(`json:"ok" broken`))
+	assert.Equal(t, []model.Tag{}, parseTags(``))
+}
+
+func TestTagsInFile(t *testing.T) {
+	parsedSources, err := parseSourceFile("structs/example.go")
+	assert.Equal(t, nil, err)
+
+	color := parsedSources.Structs[0].Fields[4]
+	assert.Equal(t, "Color", color.Name)
+	assert.Equal(t, []model.Tag{{Key: "json", Name: "COLOR_TYPE"}}, color.Tags)
+
+	tag, ok := color.LookupTag("json")
+	assert.True(t, ok)
+	assert.Equal(t, "COLOR_TYPE", tag.Name)
+	assert.False(t, tag.HasOption("omitempty"))
+
+	_, ok = color.LookupTag("db")
+	assert.False(t, ok)
+}
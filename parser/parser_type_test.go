@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldTypesInDir(t *testing.T) {
+	parsedSources, err := New().ParseSourceDir("./fieldtypes", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(parsedSources.Structs))
+
+	s := parsedSources.Structs[1]
+	assert.Equal(t, "Complex", s.Name)
+	assert.Equal(t, 11, len(s.Fields))
+
+	for idx, expected := range []string{
+		"[][]string",
+		"map[string][]Foo",
+		"*[]Foo",
+		"chan Foo",
+		"<-chan *Foo",
+		"func(in Foo, w io.Writer) (int, error)",
+		"[32]byte",
+		"struct{X int; Y int}",
+		"interface{Close() error}",
+		"map[structs.ColorType]*structs.YetAnotherStruct",
+		"[]*structs.YetAnotherStruct",
+	} {
+		assert.NotNil(t, s.Fields[idx].Type)
+		assert.Equal(t, expected, s.Fields[idx].Type.String())
+	}
+
+	{
+		matrix := s.Fields[0]
+		assertField(t, model.Field{Name: "Matrix", TypeName: "[]string", IsSlice: true}, matrix)
+		assert.Equal(t, model.KindSlice, matrix.Type.Kind)
+		assert.Equal(t, model.KindSlice, matrix.Type.Elem.Kind)
+		assert.Equal(t, "string", matrix.Type.Elem.Elem.Name)
+	}
+	{
+		lookup := s.Fields[1]
+		assertField(t, model.Field{Name: "Lookup", TypeName: "map[string][]Foo"}, lookup)
+		assert.Equal(t, model.KindMap, lookup.Type.Kind)
+		assert.Equal(t, "string", lookup.Type.Key.Name)
+		assert.Equal(t, model.KindSlice, lookup.Type.Value.Kind)
+	}
+	{
+		optional := s.Fields[2]
+		assertField(t, model.Field{Name: "Optional", TypeName: "[]Foo", IsPointer: true}, optional)
+	}
+	{
+		incoming := s.Fields[4]
+		assert.Equal(t, model.KindChan, incoming.Type.Kind)
+		assert.Equal(t, "recv", incoming.Type.ChanDir)
+	}
+	{
+		callback := s.Fields[5]
+		assert.Equal(t, model.KindFunc, callback.Type.Kind)
+		assert.Equal(t, 2, len(callback.Type.Params))
+		assert.Equal(t, "io", callback.Type.Params[1].Type.Package)
+		assert.Equal(t, 2, len(callback.Type.Results))
+	}
+	{
+		hash := s.Fields[6]
+		assert.Equal(t, model.KindArray, hash.Type.Kind)
+		assert.Equal(t, "32", hash.Type.Len)
+	}
+	{
+		foreign := s.Fields[9]
+		assertField(t, model.Field{Name: "Foreign", TypeName: "map[structs.ColorType]*structs.YetAnotherStruct"}, foreign)
+		assert.Equal(t, "github.com/MarcGrol/golangAnnotations/parser/structs", foreign.Type.Key.PackagePath)
+		assert.Equal(t, "YetAnotherStruct", foreign.Type.Value.Elem.Name)
+		assert.Equal(t, "github.com/MarcGrol/golangAnnotations/parser/structs", foreign.Type.Value.Elem.PackagePath)
+	}
+	{
+		foreignPtr := s.Fields[10]
+		assertField(t, model.Field{Name: "ForeignPtr", TypeName: "structs.YetAnotherStruct", IsSlice: true, IsPointer: true,
+			PackageName: "github.com/MarcGrol/golangAnnotations/parser/structs"}, foreignPtr)
+	}
+}
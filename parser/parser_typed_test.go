@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeCheckedInDir(t *testing.T) {
+	parsedSources, err := NewTypeChecked().ParseSourceDir("./typed", "^.*.go$", "gen_.*")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(parsedSources.Structs))
+
+	{
+		s := parsedSources.Structs[0]
+		assert.Equal(t, "Base", s.Name)
+		assert.Equal(t, "slice", s.Fields[1].Type.Underlying)
+		assert.Equal(t, "github.com/MarcGrol/golangAnnotations/parser/typed", s.Fields[1].Type.PackagePath)
+	}
+	{
+		s := parsedSources.Structs[1]
+		assert.Equal(t, "Document", s.Name)
+		assert.Equal(t, 5, len(s.Fields))
+
+		embedded := s.Fields[0]
+		assert.Equal(t, 2, len(embedded.Type.Fields))
+		assert.Equal(t, "Created", embedded.Type.Fields[0].Name)
+		assert.Equal(t, "slice", embedded.Type.Fields[1].Type.Underlying)
+
+		foreign := s.Fields[1]
+		assert.Equal(t, model.KindPointer, foreign.Type.Kind)
+		assert.Equal(t, 1, len(foreign.Type.Elem.Fields))
+		assert.Equal(t, "Y", foreign.Type.Elem.Fields[0].Name)
+		assert.Equal(t, "int", foreign.Type.Elem.Fields[0].Type.Underlying)
+
+		meta := s.Fields[2]
+		assertField(t, model.Field{Name: "Meta", TypeName: "Metadata"}, meta)
+		assert.Equal(t, "string", meta.Type.Underlying)
+		assert.Equal(t, "github.com/MarcGrol/golangAnnotations/parser/typed", meta.Type.PackagePath)
+
+		owner := s.Fields[3]
+		assert.Equal(t, "string", owner.Type.Underlying)
+		assert.Equal(t, "", owner.Type.PackagePath)
+
+		related := s.Fields[4]
+		assertField(t, model.Field{Name: "Related", TypeName: "structs.YetAnotherStruct", IsSlice: true, IsPointer: true,
+			PackageName: "github.com/MarcGrol/golangAnnotations/parser/structs"}, related)
+		assert.Equal(t, "struct", related.Type.Elem.Elem.Underlying)
+	}
+	{
+		// from typed_test.go, like the syntactic parser
+		s := parsedSources.Structs[3]
+		assert.Equal(t, "Fixture", s.Name)
+		assert.Equal(t, "int", s.Fields[0].Type.Underlying)
+		assert.Equal(t, "string", s.Fields[1].Type.Underlying)
+		assert.Equal(t, "string", s.Fields[2].Type.Underlying)
+		assert.Equal(t, "github.com/MarcGrol/golangAnnotations/parser/typed", s.Fields[2].Type.PackagePath)
+	}
+	{
+		assert.Equal(t, 1, len(parsedSources.Operations))
+		o := parsedSources.Operations[0]
+		assert.Equal(t, "Context", o.InputArgs[0].Type.Name)
+		assert.Equal(t, "context", o.InputArgs[0].Type.PackagePath)
+		assert.Equal(t, "interface", o.InputArgs[0].Type.Underlying)
+		assert.Equal(t, "context", o.InputArgs[0].PackageName)
+		assert.Equal(t, "string", o.InputArgs[1].Type.Underlying)
+		assert.Equal(t, "", o.InputArgs[1].Type.PackagePath)
+		assert.Equal(t, "struct", o.OutputArgs[0].Type.Elem.Underlying)
+	}
+}
+
+func TestTypeCheckedLikeSyntactic(t *testing.T) {
+	typed, err := NewTypeChecked().ParseSourceDir("./typed", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	syntactic, err := New().ParseSourceDir("./typed", "^.*.go$", "gen_.*")
+	assert.NoError(t, err)
+	assert.Equal(t, len(syntactic.Structs), len(typed.Structs))
+	for idx := range syntactic.Structs {
+		assert.Equal(t, syntactic.Structs[idx].Name, typed.Structs[idx].Name)
+		assert.Equal(t, len(syntactic.Structs[idx].Fields), len(typed.Structs[idx].Fields))
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/MarcGrol/golangAnnotations/parser/parserUtil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTypedefs(t *testing.T) {
+	for _, p := range []parserUtil.Parser{New(), NewTypeChecked()} {
+		parsedSources, err := p.ParseSourceDir("./typedefs", "^.*.go$", "gen_.*")
+		assert.NoError(t, err)
+
+		typedefs := map[string]model.Typedef{}
+		for _, td := range parsedSources.Typedefs {
+			typedefs[td.Name] = td
+		}
+		assert.Equal(t, 8, len(typedefs))
+
+		ids := typedefs["IDs"]
+		assert.Equal(t, []string{"// IDs is a named slice"}, ids.DocLines)
+		assert.False(t, ids.IsAlias)
+		assert.Equal(t, "[]string", ids.Type)
+		assert.Equal(t, model.KindSlice, ids.Underlying)
+		assert.Equal(t, "string", ids.Definition.Elem.Name)
+		assert.Equal(t, 1, len(ids.Operations))
+		assert.Equal(t, "Contains", ids.Operations[0].Name)
+
+		lookup := typedefs["Lookup"]
+		assert.Equal(t, "map[string]Item", lookup.Type)
+		assert.Equal(t, model.KindMap, lookup.Underlying)
+		assert.Equal(t, model.KindStruct, lookup.Definition.Value.Underlying)
+		assert.Equal(t, 1, len(lookup.Operations))
+
+		handler := typedefs["Handler"]
+		assert.Equal(t, model.KindFunc, handler.Underlying)
+		assert.Equal(t, 2, len(handler.Definition.Params))
+
+		name := typedefs["Name"]
+		assert.True(t, name.IsAlias)
+		assert.Equal(t, "string", name.Type)
+		assert.Equal(t, "string", name.Underlying)
+
+		label := typedefs["Label"]
+		assert.False(t, label.IsAlias)
+		assert.Equal(t, "string", label.Underlying)
+
+		item := typedefs["Item"]
+		assert.Equal(t, []string{"// Item is declared in a type-block"}, item.DocLines)
+		assert.Equal(t, "", item.Type)
+		assert.Equal(t, model.KindStruct, item.Underlying)
+
+		assert.Equal(t, model.KindSlice, typedefs["Items"].Underlying)
+		assert.Equal(t, 1, len(typedefs["List"].TypeParams))
+
+		// named types that refer to a typedef know their underlying kind
+		assert.Equal(t, 1, len(parsedSources.Structs))
+		fields := parsedSources.Structs[0].Fields
+		assert.Equal(t, model.KindSlice, fields[1].Type.Underlying)
+		assert.Equal(t, "string", fields[2].Type.Underlying)
+		assert.Equal(t, "", fields[2].Type.PackagePath)
+
+		// a defined type is not interchangeable with its basic underlying type
+		assert.True(t, fields[3].Type.Underlying == "" || fields[3].Type.PackagePath != "")
+	}
+}
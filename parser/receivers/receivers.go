@@ -0,0 +1,39 @@
+package receivers
+
+import "errors"
+
+type Service struct {
+	count int
+}
+
+// NewService creates a service
+func NewService(start int) *Service {
+	return &Service{count: start}
+}
+
+type Settings struct {
+	Name string
+}
+
+func NewSettings(name string) (Settings, error) {
+	if name == "" {
+		return Settings{}, errors.New("missing name")
+	}
+	return Settings{Name: name}, nil
+}
+
+// NewServices is not a constructor of Service: it returns a slice
+func NewServices() []*Service {
+	return nil
+}
+
+func (s *Service) Increment() {
+	s.count++
+}
+
+func (s Service) count2() int {
+	return s.count * 2
+}
+
+func helper() {
+}
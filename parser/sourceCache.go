@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+// cacheMaxAge is how long an entry that is not used is kept
+const cacheMaxAge = 30 * 24 * time.Hour
+
+// sourceCache keeps the parsed sources per file on disk, keyed by the content of the file. Constant values
+// can cross file boundaries, so an entry is only reused when the constants of the package are unchanged too.
+type sourceCache struct {
+	dir         string
+	toolVersion string
+}
+
+type cacheEntry struct {
+	ToolVersion string `json:"toolVersion"`
+	// PackageKey covers the content of all files of the package when the entry was made
+	PackageKey string `json:"packageKey"`
+	// ConstantsKey covers the constants of the package when the entry was made
+	ConstantsKey string              `json:"constantsKey"`
+	Sources      model.ParsedSources `json:"sources"`
+}
+
+// packageCache are the entries of the files of a single package
+type packageCache struct {
+	cache      *sourceCache
+	hashes     map[string]string
+	packageKey string
+}
+
+// forPackage returns nil when the files cannot be read: parsing will report the real problem
+func (c *sourceCache) forPackage(filenames []string) *packageCache {
+	hashes := map[string]string{}
+	packageHash := sha256.New()
+	fmt.Fprintf(packageHash, "%s\x00", c.toolVersion)
+	for _, filename := range filenames {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil
+		}
+		hashes[filename] = fmt.Sprintf("%x", sha256.Sum256(content))
+		fmt.Fprintf(packageHash, "%s\x00%s\x00", filename, hashes[filename])
+	}
+	return &packageCache{
+		cache:      c,
+		hashes:     hashes,
+		packageKey: hex.EncodeToString(packageHash.Sum(nil)),
+	}
+}
+
+func (c *sourceCache) key(filename string, contentHash string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(c.toolVersion+"\x00"+filename+"\x00"+contentHash)))
+}
+
+func (c *sourceCache) filename(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *sourceCache) lookup(key string) (cacheEntry, bool) {
+	var entry cacheEntry
+	content, err := ioutil.ReadFile(c.filename(key))
+	if err != nil {
+		return entry, false
+	}
+	err = json.Unmarshal(content, &entry)
+	if err != nil || entry.ToolVersion != c.toolVersion {
+		return entry, false
+	}
+	// mark the entry as used, so that it is not pruned
+	now := time.Now()
+	os.Chtimes(c.filename(key), now, now)
+	return entry, true
+}
+
+// lookupAll returns the sources of all files without parsing: only when none of the files of the package changed
+func (pc *packageCache) lookupAll() (map[string]model.ParsedSources, bool) {
+	fileSources := map[string]model.ParsedSources{}
+	for filename, hash := range pc.hashes {
+		entry, found := pc.cache.lookup(pc.cache.key(filename, hash))
+		if !found || entry.PackageKey != pc.packageKey {
+			return nil, false
+		}
+		fileSources[filename] = entry.Sources
+	}
+	return fileSources, len(fileSources) > 0
+}
+
+// lookupFile returns the sources of an unchanged file of a package with unchanged constants
+func (pc *packageCache) lookupFile(filename string, constantsKey string) (model.ParsedSources, bool) {
+	hash, ok := pc.hashes[filename]
+	if !ok {
+		return model.ParsedSources{}, false
+	}
+	entry, found := pc.cache.lookup(pc.cache.key(filename, hash))
+	if !found || entry.ConstantsKey != constantsKey {
+		return model.ParsedSources{}, false
+	}
+	return entry.Sources, true
+}
+
+func (pc *packageCache) storeFile(filename string, constantsKey string, sources model.ParsedSources) {
+	hash, ok := pc.hashes[filename]
+	if !ok {
+		return
+	}
+	pc.cache.store(pc.cache.key(filename, hash), cacheEntry{
+		ToolVersion:  pc.cache.toolVersion,
+		PackageKey:   pc.packageKey,
+		ConstantsKey: constantsKey,
+		Sources:      sources,
+	})
+}
+
+// constantsKey covers the package-level constants: these are the only part of other files that the sources of a file depend on
+func constantsKey(info *types.Info) string {
+	constants := []string{}
+	for _, obj := range info.Defs {
+		constant, ok := obj.(*types.Const)
+		if ok && constant.Pkg() != nil && constant.Parent() == constant.Pkg().Scope() {
+			constants = append(constants, fmt.Sprintf("%s %s %s", constant.Name(), constant.Type(), constant.Val().ExactString()))
+		}
+	}
+	sort.Strings(constants)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(constants, "\n"))))
+}
+
+// store is best-effort: a cache that cannot be written only costs performance
+func (c *sourceCache) store(key string, entry cacheEntry) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("error marshalling cache-entry: %s", err)
+		return
+	}
+	err = os.MkdirAll(c.dir, 0777)
+	if err != nil {
+		log.Printf("error creating cache-dir %s: %s", c.dir, err)
+		return
+	}
+	c.prune()
+
+	// write-and-rename so that concurrent runs never see a partial entry
+	tmpFile, err := ioutil.TempFile(c.dir, key+".*.tmp")
+	if err != nil {
+		log.Printf("error writing cache-entry: %s", err)
+		return
+	}
+	_, err = tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), c.filename(key))
+	}
+	if err != nil {
+		log.Printf("error writing cache-entry: %s", err)
+		os.Remove(tmpFile.Name())
+	}
+}
+
+var (
+	prunedMutex sync.Mutex
+	pruned      = map[string]bool{}
+)
+
+// prune removes the entries that were not used for cacheMaxAge, once per cache-dir per run:
+// every edit of a file leaves an entry behind
+func (c *sourceCache) prune() {
+	prunedMutex.Lock()
+	defer prunedMutex.Unlock()
+	if pruned[c.dir] {
+		return
+	}
+	pruned[c.dir] = true
+
+	fileInfos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, fi := range fileInfos {
+		if fi.IsDir() || time.Since(fi.ModTime()) < cacheMaxAge {
+			continue
+		}
+		if strings.HasSuffix(fi.Name(), ".json") || strings.HasSuffix(fi.Name(), ".tmp") {
+			os.Remove(filepath.Join(c.dir, fi.Name()))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const recursiveSuffix = "..."
+
+// FindSourceDirs expands patterns like "./..." into the directories below that contain matching go-files.
+// Directories without the "..." suffix are returned as-is.
+func FindSourceDirs(patterns []string, includeRegex string, excludeRegex string) ([]string, error) {
+	var includePattern = regexp.MustCompile(includeRegex)
+	var excludePattern = regexp.MustCompile(excludeRegex)
+
+	dirs := []string{}
+	found := map[string]bool{}
+	addDir := func(dir string) {
+		if !found[dir] {
+			found[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, recursiveSuffix) {
+			addDir(filepath.Clean(pattern))
+			continue
+		}
+
+		root := filepath.Clean(strings.TrimSuffix(pattern, recursiveSuffix))
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if path != root && isIgnoredDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			hasSources, err := containsSources(path, includePattern, excludePattern)
+			if err != nil {
+				return err
+			}
+			if hasSources {
+				addDir(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return dirs, err
+		}
+	}
+	return dirs, nil
+}
+
+// isIgnoredDir uses the same conventions as the go-tool
+func isIgnoredDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func containsSources(dirName string, includePattern *regexp.Regexp, excludePattern *regexp.Regexp) (bool, error) {
+	fileInfos, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		return false, err
+	}
+	for _, fi := range fileInfos {
+		if !fi.IsDir() && !excludePattern.MatchString(fi.Name()) && includePattern.MatchString(fi.Name()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
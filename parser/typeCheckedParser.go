@@ -0,0 +1,539 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+	"github.com/MarcGrol/golangAnnotations/parser/parserUtil"
+)
+
+type typeCheckedParser struct {
+	buildTags []string
+}
+
+// NewTypeChecked returns a parser that loads the package including its dependencies and uses
+// the type-checker to resolve every field to its fully qualified type and its underlying kind.
+func NewTypeChecked(buildTags ...string) parserUtil.Parser {
+	return &typeCheckedParser{
+		buildTags: buildTags,
+	}
+}
+
+func (p *typeCheckedParser) ParseSourceDir(dirName string, includeRegex string, excludeRegex string) (model.ParsedSources, error) {
+	var includePattern = regexp.MustCompile(includeRegex)
+	var excludePattern = regexp.MustCompile(excludeRegex)
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedForTest,
+		Dir:  dirName,
+		Fset: token.NewFileSet(),
+		// Like the syntactic parser, include the _test.go files
+		Tests: true,
+	}
+	if len(p.buildTags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(p.buildTags, ",")}
+	}
+	loadedPackages, err := packages.Load(cfg, ".")
+	if err != nil {
+		log.Printf("error loading package in dir %s: %s", dirName, err.Error())
+		return model.ParsedSources{}, err
+	}
+
+	v := &astVisitor{
+		FileSet: cfg.Fset,
+		Imports: map[string]string{},
+	}
+	for _, aPackage := range selectTestVariants(loadedPackages) {
+		for _, pkgErr := range aPackage.Errors {
+			if pkgErr.Kind != packages.TypeError {
+				return model.ParsedSources{}, fmt.Errorf("error loading package in dir %s: %s", dirName, pkgErr)
+			}
+			// Type-errors are to be expected: generated code the sources depend on may not yet exist
+			log.Printf("type-error in package %s: %s", aPackage.PkgPath, pkgErr)
+		}
+
+		files := map[string]*ast.File{}
+		for idx, file := range aPackage.Syntax {
+			baseName := filepath.Base(aPackage.CompiledGoFiles[idx])
+			if excludePattern.MatchString(baseName) || !includePattern.MatchString(baseName) {
+				continue
+			}
+			files[filepath.Join(dirName, baseName)] = file
+		}
+		// Only resolve what this package adds: a package and its external test-package have different scopes
+		resolved := visitorOffsets(v)
+		v.TypesInfo = aPackage.TypesInfo
+		for _, fileEntry := range sortedFileEntries(files) {
+			v.CurrentFilename = fileEntry.key
+			v.Imports = map[string]string{}
+			ast.Walk(v, &fileEntry.file)
+		}
+
+		if aPackage.Types != nil {
+			r := typeResolver{pkg: aPackage.Types, fileSet: cfg.Fset}
+			r.resolveVisitor(v, resolved)
+		}
+	}
+
+	embedOperationsInStructs(v)
+
+	embedOperationsInTypedefs(v)
+
+	embedPromotedMembersInStructs(v)
+
+	embedTypedefDocLinesInEnum(v)
+
+	parsedSources := v.parsedSources()
+	attachAnnotations(&parsedSources)
+	return parsedSources, nil
+}
+
+// selectTestVariants prefers the package compiled with its _test.go files over the package on its own,
+// and drops the generated test-main
+func selectTestVariants(loadedPackages []*packages.Package) []*packages.Package {
+	tested := map[string]bool{}
+	for _, aPackage := range loadedPackages {
+		if aPackage.ForTest != "" {
+			tested[aPackage.ForTest] = true
+		}
+	}
+	selected := []*packages.Package{}
+	for _, aPackage := range loadedPackages {
+		if aPackage.ForTest == "" && (tested[aPackage.PkgPath] || strings.HasSuffix(aPackage.PkgPath, ".test")) {
+			continue
+		}
+		selected = append(selected, aPackage)
+	}
+	return selected
+}
+
+type typeResolver struct {
+	pkg     *types.Package
+	fileSet *token.FileSet
+}
+
+// offsets counts the elements of a visitor
+type offsets struct {
+	structs, interfaces, operations, typedefs int
+}
+
+func visitorOffsets(v *astVisitor) offsets {
+	return offsets{
+		structs:    len(v.Structs),
+		interfaces: len(v.Interfaces),
+		operations: len(v.Operations),
+		typedefs:   len(v.Typedefs),
+	}
+}
+
+// resolveVisitor resolves the elements of the visitor that were added after from
+func (r typeResolver) resolveVisitor(v *astVisitor, from offsets) {
+	for idx := from.structs; idx < len(v.Structs); idx++ {
+		r.resolveStruct(&v.Structs[idx])
+	}
+	for idx := from.interfaces; idx < len(v.Interfaces); idx++ {
+		r.resolveInterface(&v.Interfaces[idx])
+	}
+	for idx := from.operations; idx < len(v.Operations); idx++ {
+		r.resolveOperation(&v.Operations[idx])
+	}
+	for idx := from.typedefs; idx < len(v.Typedefs); idx++ {
+		r.resolveTypedef(&v.Typedefs[idx])
+	}
+}
+
+func (r typeResolver) resolveTypedef(mTypedef *model.Typedef) {
+	typeName, ok := r.pkg.Scope().Lookup(mTypedef.Name).(*types.TypeName)
+	if !ok {
+		return
+	}
+	mTypedef.Underlying = underlyingKind(typeName.Type())
+	if mTypedef.IsAlias {
+		// the type of an alias is the type it refers to
+		r.resolveType(mTypedef.Definition, typeName.Type())
+	} else if mTypedef.Definition != nil && mTypedef.Definition.Kind != model.KindNamed {
+		r.resolveType(mTypedef.Definition, typeName.Type().Underlying())
+	}
+}
+
+func (r typeResolver) resolveStruct(mStruct *model.Struct) {
+	structType, ok := r.lookupType(mStruct.Name).Underlying().(*types.Struct)
+	if ok {
+		r.resolveStructFields(mStruct.Fields, structType)
+	}
+}
+
+func (r typeResolver) resolveStructFields(mFields []model.Field, structType *types.Struct) {
+	for idx := range mFields {
+		field, ok := r.lookupStructField(structType, mFields[idx])
+		if !ok {
+			continue
+		}
+		r.resolveField(&mFields[idx], field.Type())
+		if field.Embedded() {
+			r.resolveEmbeddedFields(&mFields[idx], field.Type())
+		}
+	}
+}
+
+// lookupStructField matches by name rather than by index: the parser skips fields it does not understand.
+// Blank fields share their name, so these are matched by position.
+func (r typeResolver) lookupStructField(structType *types.Struct, mField model.Field) (*types.Var, bool) {
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		field := structType.Field(idx)
+		if field.Name() != mField.Name {
+			continue
+		}
+		if field.Name() == "_" {
+			position := r.fileSet.Position(field.Pos())
+			if position.Line != mField.Position.Line || position.Column != mField.Position.Column {
+				continue
+			}
+		}
+		return field, true
+	}
+	return nil, false
+}
+
+func (r typeResolver) resolveInterface(mInterface *model.Interface) {
+	interfaceType, ok := r.lookupType(mInterface.Name).Underlying().(*types.Interface)
+	if ok {
+		r.resolveMethods(mInterface.Methods, interfaceType)
+	}
+}
+
+func (r typeResolver) resolveMethods(mOperations []model.Operation, interfaceType *types.Interface) {
+	for idx := range mOperations {
+		for mIdx := 0; mIdx < interfaceType.NumMethods(); mIdx++ {
+			method := interfaceType.Method(mIdx)
+			if method.Name() == mOperations[idx].Name {
+				r.resolveSignature(&mOperations[idx], method.Type().(*types.Signature))
+			}
+		}
+	}
+}
+
+func (r typeResolver) resolveOperation(mOperation *model.Operation) {
+	if mOperation.RelatedStruct == nil {
+		function, ok := r.pkg.Scope().Lookup(mOperation.Name).(*types.Func)
+		if ok {
+			r.resolveSignature(mOperation, function.Type().(*types.Signature))
+		}
+		return
+	}
+
+	named, ok := r.lookupType(mOperation.RelatedStruct.TypeName).(*types.Named)
+	if ok {
+		for idx := 0; idx < named.NumMethods(); idx++ {
+			method := named.Method(idx)
+			if method.Name() == mOperation.Name {
+				signature := method.Type().(*types.Signature)
+				r.resolveField(mOperation.RelatedStruct, signature.Recv().Type())
+				r.resolveSignature(mOperation, signature)
+			}
+		}
+	}
+}
+
+func (r typeResolver) resolveSignature(mOperation *model.Operation, signature *types.Signature) {
+	r.resolveTuple(mOperation.InputArgs, signature.Params())
+	r.resolveTuple(mOperation.OutputArgs, signature.Results())
+}
+
+func (r typeResolver) resolveTuple(mFields []model.Field, tuple *types.Tuple) {
+	for idx := range mFields {
+		if idx < tuple.Len() {
+			r.resolveField(&mFields[idx], tuple.At(idx).Type())
+		}
+	}
+}
+
+func (r typeResolver) lookupType(name string) types.Type {
+	typeName, ok := r.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return types.Typ[types.Invalid]
+	}
+	return typeName.Type()
+}
+
+func (r typeResolver) resolveField(mField *model.Field, typ types.Type) {
+	r.resolveType(mField.Type, typ)
+
+	// Legacy description: TypeName is the element after an optional slice and pointer
+	elem := typ
+	if mField.IsSlice {
+		slice, ok := elem.(*types.Slice)
+		if !ok {
+			return
+		}
+		elem = slice.Elem()
+	}
+	if mField.IsPointer {
+		pointer, ok := elem.(*types.Pointer)
+		if !ok {
+			return
+		}
+		elem = pointer.Elem()
+	}
+	named, ok := types.Unalias(elem).(*types.Named)
+	if ok {
+		mField.PackageName = ""
+		if named.Obj().Pkg() != nil && named.Obj().Pkg() != r.pkg {
+			mField.PackageName = packagePath(named.Obj().Pkg())
+		}
+	}
+}
+
+func (r typeResolver) resolveType(mType *model.Type, typ types.Type) {
+	if mType == nil || typ == nil {
+		return
+	}
+
+	switch mType.Kind {
+	case model.KindNamed:
+		mType.Underlying = underlyingKind(typ)
+		named, ok := types.Unalias(typ).(*types.Named)
+		if ok {
+			if named.Obj().Pkg() != nil {
+				mType.PackagePath = packagePath(named.Obj().Pkg())
+			}
+			for idx := range mType.TypeArgs {
+				if idx < named.TypeArgs().Len() {
+					r.resolveType(&mType.TypeArgs[idx], named.TypeArgs().At(idx))
+				}
+			}
+		}
+	case model.KindPointer:
+		pointer, ok := typ.(*types.Pointer)
+		if ok {
+			r.resolveType(mType.Elem, pointer.Elem())
+		}
+	case model.KindSlice:
+		slice, ok := typ.(*types.Slice)
+		if ok {
+			r.resolveType(mType.Elem, slice.Elem())
+		}
+	case model.KindArray:
+		array, ok := typ.(*types.Array)
+		if ok {
+			r.resolveType(mType.Elem, array.Elem())
+		}
+	case model.KindChan:
+		channel, ok := typ.(*types.Chan)
+		if ok {
+			r.resolveType(mType.Elem, channel.Elem())
+		}
+	case model.KindMap:
+		mapType, ok := typ.(*types.Map)
+		if ok {
+			r.resolveType(mType.Key, mapType.Key())
+			r.resolveType(mType.Value, mapType.Elem())
+		}
+	case model.KindFunc:
+		signature, ok := typ.(*types.Signature)
+		if ok {
+			r.resolveTuple(mType.Params, signature.Params())
+			r.resolveTuple(mType.Results, signature.Results())
+		}
+	case model.KindStruct:
+		structType, ok := typ.(*types.Struct)
+		if ok {
+			r.resolveStructFields(mType.Fields, structType)
+		}
+	case model.KindInterface:
+		interfaceType, ok := typ.(*types.Interface)
+		if ok {
+			r.resolveMethods(mType.Methods, interfaceType)
+		}
+	}
+}
+
+// resolveEmbeddedFields follows an embedded struct, also when it lives in another package
+func (r typeResolver) resolveEmbeddedFields(mField *model.Field, typ types.Type) {
+	if mField.Type == nil {
+		return
+	}
+	pointer, ok := typ.(*types.Pointer)
+	if ok {
+		typ = pointer.Elem()
+	}
+	structType, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	mType := mField.Type
+	if mType.Kind == model.KindPointer && mType.Elem != nil {
+		mType = mType.Elem
+	}
+	mType.Fields = r.fieldsFromStruct(structType, map[*types.Struct]bool{})
+}
+
+func (r typeResolver) fieldsFromStruct(structType *types.Struct, seen map[*types.Struct]bool) []model.Field {
+	if seen[structType] {
+		return nil
+	}
+	seen[structType] = true
+
+	mFields := []model.Field{}
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		field := structType.Field(idx)
+		mField := r.fieldFromType(field.Name(), field.Type())
+		mField.Position = extractPosition(field.Pos(), r.fileSet)
+		mField.IsEmbedded = field.Embedded()
+		mField.Tag = structType.Tag(idx)
+		if mField.Tag != "" {
+			mField.Tags = parseTags(mField.Tag)
+			mField.Tag = "`" + mField.Tag + "`"
+		}
+		if field.Embedded() {
+			embedded := field.Type()
+			pointer, ok := embedded.(*types.Pointer)
+			if ok {
+				embedded = pointer.Elem()
+			}
+			embeddedStruct, ok := embedded.Underlying().(*types.Struct)
+			if ok {
+				elemType := mField.Type
+				if elemType.Kind == model.KindPointer {
+					elemType = elemType.Elem
+				}
+				elemType.Fields = r.fieldsFromStruct(embeddedStruct, seen)
+			}
+		}
+		mFields = append(mFields, mField)
+	}
+	return mFields
+}
+
+func (r typeResolver) fieldFromType(name string, typ types.Type) model.Field {
+	mField := model.Field{
+		Name: name,
+		Type: r.typeFromType(typ),
+	}
+	elem := typ
+	slice, ok := elem.(*types.Slice)
+	if ok {
+		mField.IsSlice = true
+		elem = slice.Elem()
+	}
+	pointer, ok := elem.(*types.Pointer)
+	if ok {
+		mField.IsPointer = true
+		elem = pointer.Elem()
+	}
+	mField.TypeName = types.TypeString(elem, r.qualifier)
+	r.resolveField(&mField, typ)
+	return mField
+}
+
+func (r typeResolver) typeFromType(typ types.Type) *model.Type {
+	switch t := types.Unalias(typ).(type) {
+	case *types.Basic:
+		return &model.Type{Kind: model.KindNamed, Name: t.Name(), Underlying: t.Name()}
+	case *types.TypeParam:
+		return &model.Type{Kind: model.KindNamed, Name: t.Obj().Name(), Underlying: underlyingKind(t)}
+	case *types.Named:
+		mType := &model.Type{
+			Kind:       model.KindNamed,
+			Name:       t.Obj().Name(),
+			Underlying: underlyingKind(t),
+		}
+		if t.Obj().Pkg() != nil {
+			mType.PackagePath = packagePath(t.Obj().Pkg())
+			if t.Obj().Pkg() != r.pkg {
+				mType.Package = t.Obj().Pkg().Name()
+			}
+		}
+		for idx := 0; idx < t.TypeArgs().Len(); idx++ {
+			mType.TypeArgs = append(mType.TypeArgs, *r.typeFromType(t.TypeArgs().At(idx)))
+		}
+		return mType
+	case *types.Pointer:
+		return &model.Type{Kind: model.KindPointer, Elem: r.typeFromType(t.Elem())}
+	case *types.Slice:
+		return &model.Type{Kind: model.KindSlice, Elem: r.typeFromType(t.Elem())}
+	case *types.Array:
+		return &model.Type{Kind: model.KindArray, Len: fmt.Sprintf("%d", t.Len()), Elem: r.typeFromType(t.Elem())}
+	case *types.Chan:
+		mType := &model.Type{Kind: model.KindChan, Elem: r.typeFromType(t.Elem())}
+		switch t.Dir() {
+		case types.SendOnly:
+			mType.ChanDir = "send"
+		case types.RecvOnly:
+			mType.ChanDir = "recv"
+		}
+		return mType
+	case *types.Map:
+		return &model.Type{Kind: model.KindMap, Key: r.typeFromType(t.Key()), Value: r.typeFromType(t.Elem())}
+	case *types.Signature:
+		params := r.fieldsFromTuple(t.Params())
+		if t.Variadic() && len(params) > 0 {
+			params[len(params)-1].IsVariadic = true
+		}
+		return &model.Type{Kind: model.KindFunc, Params: params, Results: r.fieldsFromTuple(t.Results())}
+	case *types.Struct:
+		return &model.Type{Kind: model.KindStruct, Fields: r.fieldsFromStruct(t, map[*types.Struct]bool{})}
+	}
+	return &model.Type{Kind: model.KindNamed, Name: types.TypeString(typ, r.qualifier), Underlying: underlyingKind(typ)}
+}
+
+func (r typeResolver) fieldsFromTuple(tuple *types.Tuple) []model.Field {
+	mFields := []model.Field{}
+	for idx := 0; idx < tuple.Len(); idx++ {
+		mFields = append(mFields, r.fieldFromType(tuple.At(idx).Name(), tuple.At(idx).Type()))
+	}
+	return mFields
+}
+
+func (r typeResolver) qualifier(other *types.Package) string {
+	if other == r.pkg {
+		return ""
+	}
+	return other.Name()
+}
+
+// packagePath is the import-path of the package as written in source: without the vendor-directory it was found in
+func packagePath(pkg *types.Package) string {
+	importPath := pkg.Path()
+	if idx := strings.LastIndex(importPath, "/vendor/"); idx >= 0 {
+		return importPath[idx+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(importPath, "vendor/")
+}
+
+func underlyingKind(typ types.Type) string {
+	if _, ok := typ.(*types.TypeParam); ok {
+		return "typeparam"
+	}
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		return u.Name()
+	case *types.Pointer:
+		return model.KindPointer
+	case *types.Slice:
+		return model.KindSlice
+	case *types.Array:
+		return model.KindArray
+	case *types.Map:
+		return model.KindMap
+	case *types.Chan:
+		return model.KindChan
+	case *types.Signature:
+		return model.KindFunc
+	case *types.Struct:
+		return model.KindStruct
+	case *types.Interface:
+		return model.KindInterface
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package typed
+
+import (
+	stdcontext "context"
+
+	"github.com/MarcGrol/golangAnnotations/parser/structs"
+)
+
+type Metadata string
+
+type Name = string
+
+type IDs []string
+
+type Base struct {
+	Created string
+	Tags    IDs
+}
+
+type Document struct {
+	Base
+	*structs.YetAnotherStruct
+	Meta    Metadata
+	Owner   Name
+	Related []*structs.YetAnotherStruct
+}
+
+type Service struct {
+}
+
+func (s *Service) getDocument(c stdcontext.Context, name Name, meta Metadata) (*Document, error) {
+	return &Document{Meta: meta, Owner: name}, nil
+}
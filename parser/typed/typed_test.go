@@ -0,0 +1,7 @@
+package typed
+
+type Fixture struct {
+	_    int
+	_    string
+	Meta Metadata
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"go/types"
+
+	"github.com/MarcGrol/golangAnnotations/model"
+)
+
+func embedOperationsInTypedefs(visitor *astVisitor) {
+	mTypedefMap := make(map[string]*model.Typedef)
+	for idx := range visitor.Typedefs {
+		mTypedefMap[visitor.Typedefs[idx].Name] = &visitor.Typedefs[idx]
+	}
+	for idx := range visitor.Operations {
+		if visitor.Operations[idx].RelatedStruct != nil {
+			mTypedef, ok := mTypedefMap[visitor.Operations[idx].RelatedStruct.TypeName]
+			if ok {
+				mOperation := visitor.Operations[idx]
+				mTypedef.Operations = append(mTypedef.Operations, &mOperation)
+			}
+		}
+	}
+}
+
+// resolveTypedefKinds determines the underlying kind of the typedefs and of the named types that refer to them,
+// so that the sources of a single package provide what otherwise requires type-checked parsing.
+// A defined type with a basic underlying type is left unresolved: only its package-path, which requires
+// type-checked parsing, tells it apart from an alias
+func resolveTypedefKinds(visitor *astVisitor) {
+	mTypedefMap := make(map[string]*model.Typedef)
+	for idx := range visitor.Typedefs {
+		mTypedefMap[visitor.Typedefs[idx].Name] = &visitor.Typedefs[idx]
+	}
+	for idx := range visitor.Typedefs {
+		visitor.Typedefs[idx].Underlying = typedefKind(visitor.Typedefs[idx], mTypedefMap, map[string]bool{})
+	}
+
+	kinds := map[string]string{}
+	for _, mTypedef := range visitor.Typedefs {
+		if mTypedef.Underlying == "" {
+			continue
+		}
+		// Like the type-checker: only an alias of a basic type can be used as the basic type itself
+		if isBasicKind(mTypedef.Underlying) && !isAliasOfBasic(mTypedef, mTypedefMap, map[string]bool{}) {
+			continue
+		}
+		kinds[mTypedef.Name] = mTypedef.Underlying
+	}
+	for idx := range visitor.Structs {
+		resolveFieldKinds(visitor.Structs[idx].Fields, kinds)
+	}
+	for idx := range visitor.Interfaces {
+		resolveOperationKinds(visitor.Interfaces[idx].Methods, kinds)
+	}
+	for idx := range visitor.Operations {
+		resolveOperationKinds(visitor.Operations[idx:idx+1], kinds)
+		if visitor.Operations[idx].RelatedStruct != nil {
+			resolveTypeKinds(visitor.Operations[idx].RelatedStruct.Type, kinds)
+		}
+	}
+	for idx := range visitor.Typedefs {
+		resolveTypeKinds(visitor.Typedefs[idx].Definition, kinds)
+	}
+}
+
+func typedefKind(mTypedef model.Typedef, mTypedefMap map[string]*model.Typedef, seen map[string]bool) string {
+	if mTypedef.Underlying != "" {
+		return mTypedef.Underlying
+	}
+	definition := mTypedef.Definition
+	if definition == nil {
+		return ""
+	}
+	if definition.Kind != model.KindNamed {
+		return definition.Kind
+	}
+	if definition.Package != "" {
+		// requires type-checked parsing
+		return ""
+	}
+	other, ok := mTypedefMap[definition.Name]
+	if ok {
+		if seen[other.Name] {
+			return ""
+		}
+		seen[other.Name] = true
+		return typedefKind(*other, mTypedefMap, seen)
+	}
+	universe, ok := types.Universe.Lookup(definition.Name).(*types.TypeName)
+	if ok {
+		return underlyingKind(universe.Type())
+	}
+	return ""
+}
+
+func isBasicKind(kind string) bool {
+	universe, ok := types.Universe.Lookup(kind).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	_, ok = universe.Type().(*types.Basic)
+	return ok
+}
+
+func isAliasOfBasic(mTypedef model.Typedef, mTypedefMap map[string]*model.Typedef, seen map[string]bool) bool {
+	definition := mTypedef.Definition
+	if !mTypedef.IsAlias || definition == nil || definition.Kind != model.KindNamed || definition.Package != "" {
+		return false
+	}
+	other, ok := mTypedefMap[definition.Name]
+	if ok {
+		if seen[other.Name] {
+			return false
+		}
+		seen[other.Name] = true
+		return isAliasOfBasic(*other, mTypedefMap, seen)
+	}
+	return isBasicKind(definition.Name)
+}
+
+func resolveOperationKinds(mOperations []model.Operation, kinds map[string]string) {
+	for idx := range mOperations {
+		resolveFieldKinds(mOperations[idx].InputArgs, kinds)
+		resolveFieldKinds(mOperations[idx].OutputArgs, kinds)
+	}
+}
+
+func resolveFieldKinds(mFields []model.Field, kinds map[string]string) {
+	for idx := range mFields {
+		resolveTypeKinds(mFields[idx].Type, kinds)
+	}
+}
+
+func resolveTypeKinds(mType *model.Type, kinds map[string]string) {
+	if mType == nil {
+		return
+	}
+	if mType.Kind == model.KindNamed && mType.Package == "" && mType.Underlying == "" {
+		mType.Underlying = kinds[mType.Name]
+	}
+	for idx := range mType.TypeArgs {
+		resolveTypeKinds(&mType.TypeArgs[idx], kinds)
+	}
+	resolveTypeKinds(mType.Elem, kinds)
+	resolveTypeKinds(mType.Key, kinds)
+	resolveTypeKinds(mType.Value, kinds)
+	resolveFieldKinds(mType.Params, kinds)
+	resolveFieldKinds(mType.Results, kinds)
+	resolveFieldKinds(mType.Fields, kinds)
+	resolveOperationKinds(mType.Methods, kinds)
+}
@@ -0,0 +1,41 @@
+package typedefs
+
+import "net/http"
+
+// IDs is a named slice
+type IDs []string
+
+type Lookup map[string]Item
+
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+type Name = string
+
+type Label Name
+
+type (
+	// Item is declared in a type-block
+	Item struct {
+		Name  string
+		Tags  IDs
+		Alias Name
+		Label Label
+	}
+
+	Items []Item
+)
+
+type List[T any] []T
+
+func (ids IDs) Contains(id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (l Lookup) Get(name string) Item {
+	return l[name]
+}